@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+const maxHistoryLength = 1000
+
+var errInterrupted = errors.New("interrupted")
+
+// lineEditor reads lines from a terminal with cursor movement, history and tab completion. When the input
+// is not a terminal, lines are read as they are without any editing.
+type lineEditor struct {
+	in          *os.File
+	out         io.Writer
+	reader      *bufio.Reader
+	prompt      string
+	history     []string
+	historyFile string
+	complete    func(prefix string) []string
+}
+
+func newLineEditor(in *os.File, out io.Writer, prompt, historyFile string, complete func(prefix string) []string) *lineEditor {
+	editor := &lineEditor{
+		in:          in,
+		out:         out,
+		reader:      bufio.NewReader(in),
+		prompt:      prompt,
+		history:     make([]string, 0),
+		historyFile: historyFile,
+		complete:    complete,
+	}
+
+	editor.loadHistory()
+
+	return editor
+}
+
+// ReadLine reads a single line of input. It returns errInterrupted if the user pressed Ctrl+C, and io.EOF
+// if the user pressed Ctrl+D on an empty line or the input was closed.
+func (e *lineEditor) ReadLine() (string, error) {
+	restore, err := makeRaw(int(e.in.Fd()))
+
+	if err != nil {
+		fmt.Fprint(e.out, e.prompt)
+
+		line, err := e.reader.ReadString('\n')
+
+		if err != nil && (err != io.EOF || len(line) < 1) {
+			return "", err
+		}
+
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	defer restore()
+
+	var (
+		buf          []rune = make([]rune, 0)
+		pos          int    = 0
+		historyIndex int    = len(e.history)
+		current      string = ""
+		lastTab      bool   = false
+	)
+
+	e.refresh(buf, pos)
+
+	for {
+		r, _, err := e.reader.ReadRune()
+
+		if err != nil {
+			return "", err
+		}
+
+		isTab := false
+
+		switch r {
+		case '\r', '\n':
+			{
+				fmt.Fprint(e.out, "\r\n")
+
+				line := string(buf)
+
+				e.addHistory(line)
+
+				return line, nil
+			}
+		case 0x03: // Ctrl+C
+			{
+				fmt.Fprint(e.out, "^C\r\n")
+
+				return "", errInterrupted
+			}
+		case 0x04: // Ctrl+D
+			{
+				if len(buf) < 1 {
+					fmt.Fprint(e.out, "\r\n")
+
+					return "", io.EOF
+				}
+
+				if pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		case 0x7F, 0x08: // Backspace
+			{
+				if pos > 0 {
+					buf = append(buf[:pos-1], buf[pos:]...)
+					pos--
+				}
+			}
+		case 0x01: // Ctrl+A
+			pos = 0
+		case 0x05: // Ctrl+E
+			pos = len(buf)
+		case 0x0B: // Ctrl+K
+			buf = buf[:pos]
+		case 0x15: // Ctrl+U
+			{
+				buf = append([]rune{}, buf[pos:]...)
+				pos = 0
+			}
+		case 0x17: // Ctrl+W
+			{
+				start := pos
+
+				for start > 0 && unicode.IsSpace(buf[start-1]) {
+					start--
+				}
+
+				for start > 0 && !unicode.IsSpace(buf[start-1]) {
+					start--
+				}
+
+				buf = append(buf[:start], buf[pos:]...)
+				pos = start
+			}
+		case 0x0C: // Ctrl+L
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case '\t':
+			{
+				isTab = true
+
+				buf, pos = e.completeLine(buf, pos, lastTab)
+			}
+		case 0x1B: // Escape sequences for the arrow, home, end and delete keys
+			{
+				switch e.readEscapeSequence() {
+				case "[A", "OA":
+					{
+						if historyIndex > 0 {
+							if historyIndex == len(e.history) {
+								current = string(buf)
+							}
+
+							historyIndex--
+
+							buf = []rune(e.history[historyIndex])
+							pos = len(buf)
+						}
+					}
+				case "[B", "OB":
+					{
+						if historyIndex < len(e.history) {
+							historyIndex++
+
+							if historyIndex == len(e.history) {
+								buf = []rune(current)
+							} else {
+								buf = []rune(e.history[historyIndex])
+							}
+
+							pos = len(buf)
+						}
+					}
+				case "[C", "OC":
+					pos = min(pos+1, len(buf))
+				case "[D", "OD":
+					pos = max(pos-1, 0)
+				case "[H", "OH", "[1~", "[7~":
+					pos = 0
+				case "[F", "OF", "[4~", "[8~":
+					pos = len(buf)
+				case "[3~":
+					{
+						if pos < len(buf) {
+							buf = append(buf[:pos], buf[pos+1:]...)
+						}
+					}
+				}
+			}
+		default:
+			{
+				if unicode.IsPrint(r) {
+					buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+					pos++
+				}
+			}
+		}
+
+		lastTab = isTab
+
+		e.refresh(buf, pos)
+	}
+}
+
+// readEscapeSequence reads the rest of an escape sequence after the escape byte.
+func (e *lineEditor) readEscapeSequence() string {
+	result := make([]rune, 0, 4)
+
+	for {
+		r, _, err := e.reader.ReadRune()
+
+		if err != nil {
+			return string(result)
+		}
+
+		result = append(result, r)
+
+		// The sequence ends with a letter or a tilde, after the introducing bracket or letter O.
+		if len(result) > 1 && (unicode.IsLetter(r) || r == '~') {
+			return string(result)
+		}
+
+		if len(result) == 1 && r != '[' && r != 'O' {
+			return string(result)
+		}
+	}
+}
+
+// completeLine completes the command at the start of the line. A single match is completed in full,
+// multiple matches are completed up to their common prefix, and pressing tab again lists the matches.
+func (e *lineEditor) completeLine(buf []rune, pos int, listMatches bool) ([]rune, int) {
+	if e.complete == nil || strings.ContainsFunc(string(buf[:pos]), unicode.IsSpace) {
+		fmt.Fprint(e.out, "\a")
+
+		return buf, pos
+	}
+
+	prefix := string(buf[:pos])
+	matches := e.complete(prefix)
+
+	if len(matches) < 1 {
+		fmt.Fprint(e.out, "\a")
+
+		return buf, pos
+	}
+
+	completion := matches[0]
+
+	if len(matches) == 1 {
+		completion += " "
+	} else {
+		for _, match := range matches[1:] {
+			for !strings.HasPrefix(match, completion) {
+				completion = completion[:len(completion)-1]
+			}
+		}
+
+		if completion == prefix && listMatches {
+			fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(matches, "  "))
+		}
+	}
+
+	result := append([]rune(completion), buf[pos:]...)
+
+	return result, len([]rune(completion))
+}
+
+// refresh redraws the prompt and the line, and moves the cursor to its position.
+func (e *lineEditor) refresh(buf []rune, pos int) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, string(buf))
+
+	if offset := len(buf) - pos; offset > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", offset)
+	}
+}
+
+func (e *lineEditor) loadHistory() {
+	if len(e.historyFile) < 1 {
+		return
+	}
+
+	f, err := os.Open(e.historyFile)
+
+	if err != nil {
+		return
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		if line := scanner.Text(); len(line) > 0 {
+			e.history = append(e.history, line)
+		}
+	}
+
+	if len(e.history) > maxHistoryLength {
+		e.history = e.history[len(e.history)-maxHistoryLength:]
+	}
+}
+
+// addHistory adds the line to the history and appends it to the history file, skipping empty lines and
+// lines that repeat the previous line.
+func (e *lineEditor) addHistory(line string) {
+	if len(strings.TrimSpace(line)) < 1 || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+
+	e.history = append(e.history, line)
+
+	if len(e.history) > maxHistoryLength {
+		e.history = e.history[1:]
+	}
+
+	if len(e.historyFile) < 1 {
+		return
+	}
+
+	f, err := os.OpenFile(e.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return
+	}
+
+	defer f.Close()
+
+	fmt.Fprintln(f, line)
+}
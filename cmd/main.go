@@ -21,12 +21,16 @@ var (
 )
 
 type passedOptions struct {
-	Type        string `short:"t" long:"type" description:"The type of status to retrieve" default:"java"`
-	Timeout     uint   `short:"T" long:"timeout" description:"The amount of seconds before the status retrieval times out" default:"5"`
-	DisableSRV  bool   `short:"S" long:"disable-srv" description:"Disables SRV lookup"`
-	Debug       bool   `short:"D" long:"debug" description:"Enables debug printing to the console"`
-	Protocol    int    `short:"p" long:"protocol" description:"Sets the protocol version for the status ping (Java Edition only)"`
-	DisablePing bool   `short:"P" long:"disable-ping" description:"Disables the extra ping-pong payloads during status retrieval"`
+	Type        string   `short:"t" long:"type" description:"The type of status to retrieve" default:"java"`
+	Timeout     uint     `short:"T" long:"timeout" description:"The amount of seconds before the status retrieval times out" default:"5"`
+	DisableSRV  bool     `short:"S" long:"disable-srv" description:"Disables SRV lookup"`
+	Debug       bool     `short:"D" long:"debug" description:"Enables debug printing to the console"`
+	Protocol    int      `short:"p" long:"protocol" description:"Sets the protocol version for the status ping (Java Edition only)"`
+	DisablePing bool     `short:"P" long:"disable-ping" description:"Disables the extra ping-pong payloads during status retrieval"`
+	Password    string   `short:"w" long:"password" description:"The password used to log in to RCON, read from MCUTIL_RCON_PASSWORD or prompted for if missing (rcon type only)"`
+	Commands    []string `short:"c" long:"command" description:"Executes the command over RCON and exits instead of starting an interactive session, may be repeated (rcon type only)"`
+	History     string   `long:"history" description:"The file the RCON command history is saved to, defaults to ~/.mcutil_rcon_history (rcon type only)"`
+	NoColor     bool     `long:"no-color" description:"Disables colored RCON output (rcon type only)"`
 }
 
 func init() {
@@ -62,10 +66,16 @@ func init() {
 
 				break
 			}
-		case "bedrock":
+		case "bedrock", "qbedrock":
 			{
 				port = 19132
 
+				break
+			}
+		case "rcon":
+			{
+				port = 25575
+
 				break
 			}
 		default:
@@ -85,6 +95,16 @@ func init() {
 }
 
 func main() {
+	if opts.Type == "rcon" {
+		if err := runRCON(); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	var (
 		result any
 		err    error
@@ -131,6 +151,9 @@ func main() {
 		{
 			result, err = status.Bedrock(ctx, host, port, options.StatusBedrock{
 				Timeout: time.Duration(opts.Timeout) * time.Second,
+				Retry: options.Retry{
+					Attempts: 3,
+				},
 			})
 
 			break
@@ -145,6 +168,15 @@ func main() {
 		{
 			result, err = query.Full(ctx, host, port)
 
+			break
+		}
+	case "qbedrock":
+		{
+			result, err = query.Full(ctx, host, port, options.Query{
+				Timeout: time.Duration(opts.Timeout) * time.Second,
+				Bedrock: true,
+			})
+
 			break
 		}
 	default:
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+var (
+	// helpCommandRegExp matches the command names in the output of the help command, which vanilla servers
+	// concatenate without any separator and other servers list one per line.
+	helpCommandRegExp = regexp.MustCompile(`(?:^|[^A-Za-z0-9_:./-])/([a-z0-9_][a-z0-9_:.-]*)`)
+)
+
+// runRCON logs in to the server over RCON and executes the commands passed as flags, the commands read
+// from standard input, or the commands typed into an interactive session.
+func runRCON() error {
+	password, err := rconPassword()
+
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(opts.Timeout) * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	defer cancel()
+
+	client, err := rcon.DialContext(ctx, host, port, options.RCON{
+		Timeout: timeout,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	if err := client.LoginContext(ctx, password); err != nil {
+		return err
+	}
+
+	color := !opts.NoColor && isTerminal(int(os.Stdout.Fd()))
+
+	if len(opts.Commands) > 0 {
+		for _, command := range opts.Commands {
+			if err := executeRCON(client, command, color); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if !isTerminal(int(os.Stdin.Fd())) {
+		scanner := bufio.NewScanner(os.Stdin)
+
+		for scanner.Scan() {
+			if command := strings.TrimSpace(scanner.Text()); len(command) > 0 {
+				if err := executeRCON(client, command, color); err != nil {
+					return err
+				}
+			}
+		}
+
+		return scanner.Err()
+	}
+
+	commands := learnCommands(client)
+
+	editor := newLineEditor(os.Stdin, os.Stdout, "> ", rconHistoryFile(), func(prefix string) []string {
+		return completeCommand(commands, prefix)
+	})
+
+	fmt.Printf("Logged in to %s:%d, type \"exit\" or press Ctrl+D to quit\n", host, port)
+
+	for {
+		line, err := editor.ReadLine()
+
+		if errors.Is(err, errInterrupted) {
+			continue
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		command := strings.TrimSpace(line)
+
+		switch command {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		}
+
+		if err := executeRCON(client, command, color); err != nil {
+			if errors.Is(err, rcon.ErrConnectionLost) || errors.Is(err, rcon.ErrNotConnected) {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+	}
+}
+
+// executeRCON executes the command and prints the output, rendering any formatting codes as terminal
+// colors if color is true.
+func executeRCON(client *rcon.Client, command string, color bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(opts.Timeout)*time.Second)
+
+	defer cancel()
+
+	result, err := client.ExecuteFormatted(ctx, command)
+
+	if err != nil {
+		return err
+	}
+
+	if len(result.Raw) < 1 {
+		return nil
+	}
+
+	text := result.Clean
+
+	if color {
+		text = result.ANSI()
+	}
+
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	fmt.Print(text)
+
+	return nil
+}
+
+// learnCommands returns the sorted command names listed in the output of the help command.
+func learnCommands(client *rcon.Client) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(opts.Timeout)*time.Second)
+
+	defer cancel()
+
+	output, err := client.Execute(ctx, "help")
+
+	if err != nil {
+		return nil
+	}
+
+	result := make([]string, 0)
+
+	for _, match := range helpCommandRegExp.FindAllStringSubmatch(output, -1) {
+		if !contains(result, match[1]) {
+			result = append(result, match[1])
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// completeCommand returns the commands that start with the prefix, keeping a leading slash if the prefix
+// has one.
+func completeCommand(commands []string, prefix string) []string {
+	slash := strings.HasPrefix(prefix, "/")
+	result := make([]string, 0)
+
+	for _, command := range commands {
+		if strings.HasPrefix(command, strings.TrimPrefix(prefix, "/")) {
+			if slash {
+				command = "/" + command
+			}
+
+			result = append(result, command)
+		}
+	}
+
+	return result
+}
+
+// rconPassword returns the password from the flags or the MCUTIL_RCON_PASSWORD environment variable, or
+// prompts for it if the input is a terminal.
+func rconPassword() (string, error) {
+	if len(opts.Password) > 0 {
+		return opts.Password, nil
+	}
+
+	if value, ok := os.LookupEnv("MCUTIL_RCON_PASSWORD"); ok {
+		return value, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if !isTerminal(fd) {
+		return "", errors.New("missing --password flag or MCUTIL_RCON_PASSWORD environment variable")
+	}
+
+	fmt.Print("Password: ")
+
+	restore, err := disableEcho(fd)
+
+	if err != nil {
+		return "", err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+
+	restore()
+
+	fmt.Println()
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// rconHistoryFile returns the path of the file the command history is saved to, or an empty string if
+// the history should not be saved.
+func rconHistoryFile() string {
+	if len(opts.History) > 0 {
+		return opts.History
+	}
+
+	dir, err := os.UserHomeDir()
+
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, ".mcutil_rcon_history")
+}
+
+func contains[T comparable](arr []T, v T) bool {
+	for _, a := range arr {
+		if a == v {
+			return true
+		}
+	}
+
+	return false
+}
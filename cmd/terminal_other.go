@@ -0,0 +1,20 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package main
+
+import "errors"
+
+var errUnsupportedTerminal = errors.New("terminal control is not supported on this platform")
+
+// isTerminal always returns false, so input is read line by line without line editing.
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (func(), error) {
+	return nil, errUnsupportedTerminal
+}
+
+func disableEcho(fd int) (func(), error) {
+	return nil, errUnsupportedTerminal
+}
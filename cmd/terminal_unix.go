@@ -0,0 +1,54 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package main
+
+import "golang.org/x/sys/unix"
+
+// isTerminal returns whether the file descriptor is a terminal.
+func isTerminal(fd int) bool {
+	_, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+
+	return err == nil
+}
+
+// makeRaw puts the terminal into raw mode, where input is read one key at a time without echo, and
+// returns a function that restores the previous mode.
+func makeRaw(fd int) (func(), error) {
+	return setTermios(fd, func(termios *unix.Termios) {
+		termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+		termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		termios.Cflag &^= unix.CSIZE | unix.PARENB
+		termios.Cflag |= unix.CS8
+		termios.Cc[unix.VMIN] = 1
+		termios.Cc[unix.VTIME] = 0
+	})
+}
+
+// disableEcho stops the terminal from echoing input, and returns a function that restores the previous
+// mode.
+func disableEcho(fd int) (func(), error) {
+	return setTermios(fd, func(termios *unix.Termios) {
+		termios.Lflag &^= unix.ECHO
+		termios.Lflag |= unix.ICANON | unix.ISIG
+	})
+}
+
+func setTermios(fd int, modify func(termios *unix.Termios)) (func(), error) {
+	termios, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *termios
+
+	modify(termios)
+
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, termios); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		unix.IoctlSetTermios(fd, ioctlWriteTermios, &previous)
+	}, nil
+}
@@ -130,3 +130,45 @@ func (c Color) ToHex() string {
 		return "#ffffff"
 	}
 }
+
+// ToANSI returns the ANSI escape sequence that sets the terminal foreground color to the color.
+func (c Color) ToANSI() string {
+	switch c {
+	case Black:
+		return "\x1b[30m"
+	case DarkBlue:
+		return "\x1b[34m"
+	case DarkGreen:
+		return "\x1b[32m"
+	case DarkAqua:
+		return "\x1b[36m"
+	case DarkRed:
+		return "\x1b[31m"
+	case DarkPurple:
+		return "\x1b[35m"
+	case Gold:
+		return "\x1b[33m"
+	case Gray:
+		return "\x1b[37m"
+	case DarkGray:
+		return "\x1b[90m"
+	case Blue:
+		return "\x1b[94m"
+	case Green:
+		return "\x1b[92m"
+	case Aqua:
+		return "\x1b[96m"
+	case Red:
+		return "\x1b[91m"
+	case LightPurple:
+		return "\x1b[95m"
+	case Yellow:
+		return "\x1b[93m"
+	case White:
+		return "\x1b[97m"
+	case MinecoinGold:
+		return "\x1b[38;2;221;214;5m"
+	default:
+		return ""
+	}
+}
@@ -47,6 +47,23 @@ func (d Decorator) ToRaw() string {
 	}
 }
 
+// ToANSI returns the ANSI escape sequence that applies the decorator to terminal text. Obfuscated text has
+// no terminal equivalent and is returned as an empty string.
+func (d Decorator) ToANSI() string {
+	switch d {
+	case Bold:
+		return "\x1b[1m"
+	case Strikethrough:
+		return "\x1b[9m"
+	case Underlined:
+		return "\x1b[4m"
+	case Italic:
+		return "\x1b[3m"
+	default:
+		return ""
+	}
+}
+
 // Parse attempts to return a Decorator type based on a formatting code string, formatting name string, or a Decorator type itself.
 func Parse(value any) (Decorator, bool) {
 	switch value {
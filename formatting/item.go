@@ -114,6 +114,23 @@ func (i Item) HTML() string {
 	return fmt.Sprintf("<span%s%s>%s</span>", rawClass, rawStyle, html.EscapeString(i.Text))
 }
 
+// ANSI returns the text of the formatting item wrapped in ANSI escape sequences for display in a terminal.
+func (i Item) ANSI() (result string) {
+	if i.Color == nil && len(i.Decorators) < 1 {
+		return i.Text
+	}
+
+	if i.Color != nil {
+		result += i.Color.ToANSI()
+	}
+
+	for _, decorator := range i.Decorators {
+		result += decorator.ToANSI()
+	}
+
+	return result + i.Text + "\x1b[0m"
+}
+
 // IsSameAs returns whether the formatting is identical to another
 // item.
 func (i Item) IsSameAs(j Item) bool {
@@ -164,6 +181,14 @@ func toHTML(tree []Item) (result string) {
 	return
 }
 
+func toANSI(tree []Item) (result string) {
+	for _, v := range tree {
+		result += v.ANSI()
+	}
+
+	return
+}
+
 func contains[T comparable](arr []T, v T) bool {
 	for _, a := range arr {
 		if a == v {
@@ -34,6 +34,11 @@ func Parse(input any) (*Result, error) {
 	}, nil
 }
 
+// ANSI returns the formatted text using ANSI escape sequences, for display in a terminal.
+func (r Result) ANSI() string {
+	return toANSI(r.Tree)
+}
+
 func parseAny(input any, parent map[string]any) ([]Item, error) {
 	result := make([]Item, 0)
 
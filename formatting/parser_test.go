@@ -15,3 +15,15 @@ func TestFormatting(t *testing.T) {
 
 	t.Logf("%+v\n", res.Tree)
 }
+
+func TestFormattingANSI(t *testing.T) {
+	res, err := formatting.Parse("\u00A7cRed \u00A7lbold\u00A7r plain")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "\x1b[91mRed \x1b[0m\x1b[91m\x1b[1mbold\x1b[0m plain"; res.ANSI() != expected {
+		t.Fatalf("expected %q, received %q", expected, res.ANSI())
+	}
+}
@@ -6,4 +6,4 @@ toolchain go1.23.4
 
 require github.com/jessevdk/go-flags v1.6.1
 
-require golang.org/x/sys v0.33.0 // indirect
+require golang.org/x/sys v0.33.0
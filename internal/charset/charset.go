@@ -0,0 +1,21 @@
+package charset
+
+import "unicode/utf8"
+
+// Decode decodes the data as UTF-8 if it is valid UTF-8, otherwise as ISO-8859-1, which is used by some
+// servers for text that does not fit in ASCII.
+func Decode[T ~string | ~[]byte](data T) string {
+	value := string(data)
+
+	if utf8.ValidString(value) {
+		return value
+	}
+
+	result := make([]rune, len(value))
+
+	for i := 0; i < len(value); i++ {
+		result[i] = rune(value[i])
+	}
+
+	return string(result)
+}
@@ -0,0 +1,97 @@
+package udp
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Conn is a UDP socket used to exchange datagrams with a single server.
+type Conn struct {
+	conn      *net.UDPConn
+	addr      *net.UDPAddr
+	connected bool
+}
+
+// Dial opens a UDP socket to the server. A connected socket only receives datagrams sent from the server
+// address, while an unconnected socket receives datagrams from any address. Unconnected sockets are required
+// when the server replies from a different address than the one the request was sent to, such as servers
+// behind NAT, anycast or multi-homed hosts. Replies on an unconnected socket must be matched by their contents.
+func Dial(hostname string, port uint16, timeout time.Duration, unconnected bool) (*Conn, error) {
+	if !unconnected {
+		conn, err := net.DialTimeout("udp", net.JoinHostPort(hostname, strconv.Itoa(int(port))), timeout)
+
+		if err != nil {
+			return nil, err
+		}
+
+		udpConn := conn.(*net.UDPConn)
+
+		return &Conn{
+			conn:      udpConn,
+			addr:      udpConn.RemoteAddr().(*net.UDPAddr),
+			connected: true,
+		}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, hostname)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addrs) < 1 {
+		return nil, &net.DNSError{Err: "no such host", Name: hostname, IsNotFound: true}
+	}
+
+	conn, err := net.ListenUDP("udp", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conn{
+		conn:      conn,
+		addr:      &net.UDPAddr{IP: addrs[0].IP, Port: int(port), Zone: addrs[0].Zone},
+		connected: false,
+	}, nil
+}
+
+// Write sends the datagram to the server address.
+func (c *Conn) Write(data []byte) (int, error) {
+	if c.connected {
+		return c.conn.Write(data)
+	}
+
+	return c.conn.WriteToUDP(data, c.addr)
+}
+
+// ReadFrom reads a single datagram and returns the address it was received from.
+func (c *Conn) ReadFrom(data []byte) (int, net.Addr, error) {
+	return c.conn.ReadFromUDP(data)
+}
+
+// RemoteAddr returns the server address that datagrams are sent to.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// SetDeadline sets the read and write deadlines of the socket.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the socket.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// Close closes the socket.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
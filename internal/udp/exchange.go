@@ -0,0 +1,129 @@
+// Package udp implements the request and reply exchange shared by the UDP-based protocols.
+package udp
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
+)
+
+// MaxDatagramSize is the largest payload that can be received in a single UDP datagram.
+const MaxDatagramSize = 65535
+
+// maxReadSize is one byte larger than the largest accepted datagram, so that a datagram that was
+// truncated when reading it can be detected.
+const maxReadSize = MaxDatagramSize + 1
+
+// Exchange sends the packet built by request and reads datagrams until accept returns true or an error. If
+// no datagram is accepted before the attempt timeout, request is called again to build a retransmission.
+// Datagrams that are not accepted, such as duplicate or late replies to other requests, are ignored. The
+// exchange stops early with the error of the context if it is done.
+func Exchange(ctx context.Context, conn *Conn, deadline time.Time, retry options.Retry, request func(attempt int) ([]byte, error), accept func(data []byte, addr net.Addr) (bool, error)) error {
+	var (
+		attempts       int           = max(retry.Attempts, 1)
+		backoff        float64       = retry.Backoff
+		attemptTimeout time.Duration = retry.AttemptTimeout
+		data           []byte        = make([]byte, maxReadSize)
+	)
+
+	if backoff < 1 {
+		backoff = 2
+	}
+
+	if attemptTimeout <= 0 {
+		attemptTimeout = splitTimeout(time.Until(deadline), attempts, backoff)
+	}
+
+	// Unblock any pending read once the context is done.
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Now())
+	})
+
+	defer stop()
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		packet, err := request(attempt)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err = conn.Write(packet); err != nil {
+			return err
+		}
+
+		attemptDeadline := time.Now().Add(attemptTimeout)
+
+		// The last attempt waits for a reply until the overall deadline.
+		if attempt == attempts-1 || attemptDeadline.After(deadline) {
+			attemptDeadline = deadline
+		}
+
+		if err = conn.SetReadDeadline(attemptDeadline); err != nil {
+			return err
+		}
+
+		for {
+			n, addr, err := conn.ReadFrom(data)
+
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+
+				if isTimeout(err) && attempt < attempts-1 && time.Now().Before(deadline) {
+					break
+				}
+
+				return err
+			}
+
+			if n > MaxDatagramSize {
+				return mcerrors.NewOversize("udp", "datagram", MaxDatagramSize, int64(n))
+			}
+
+			ok, err := accept(data[:n], addr)
+
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				return nil
+			}
+		}
+
+		attemptTimeout = time.Duration(float64(attemptTimeout) * backoff)
+	}
+
+	return nil
+}
+
+// splitTimeout returns the timeout of the first attempt so that the timeouts of all attempts, each
+// multiplied by the backoff, add up to the total timeout.
+func splitTimeout(total time.Duration, attempts int, backoff float64) time.Duration {
+	var (
+		sum        float64 = 0
+		multiplier float64 = 1
+	)
+
+	for i := 0; i < attempts; i++ {
+		sum += multiplier
+		multiplier *= backoff
+	}
+
+	return time.Duration(float64(total) / sum)
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+
+	return ok && netErr.Timeout()
+}
@@ -0,0 +1,177 @@
+// Package mcerrors contains the error kinds returned by the status, query, RCON and vote packages. Every error
+// returned by those packages that matches one of the kinds can be checked using errors.Is(), and the details of
+// protocol errors can be read using errors.As() with a *ProtocolError.
+package mcerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+var (
+	// ErrDNS means the hostname of the server could not be resolved.
+	ErrDNS = errors.New("mcerrors: DNS lookup failed")
+	// ErrConnectionRefused means the server actively refused the connection, usually because nothing is listening on the port.
+	ErrConnectionRefused = errors.New("mcerrors: connection refused")
+	// ErrTimeout means the server did not respond before the timeout or deadline.
+	ErrTimeout = errors.New("mcerrors: timed out")
+	// ErrProtocol means the server sent data that does not follow the protocol. All protocol errors match this kind.
+	ErrProtocol = errors.New("mcerrors: protocol violation")
+	// ErrUnexpectedPacket means the server sent a packet with a type other than the expected one.
+	ErrUnexpectedPacket = errors.New("mcerrors: unexpected packet")
+	// ErrPayloadMismatch means the server replied with a value that does not match the value sent to it, such as a ping payload or session ID.
+	ErrPayloadMismatch = errors.New("mcerrors: payload mismatch")
+	// ErrOversize means data is larger than the protocol allows.
+	ErrOversize = errors.New("mcerrors: data too large")
+	// ErrAuth means the server rejected the credentials used to authenticate.
+	ErrAuth = errors.New("mcerrors: authentication failed")
+)
+
+// Error is an error of one of the error kinds, optionally caused by another error.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+// New returns a new error of the kind with the message.
+func New(kind error, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Message: message,
+		Err:     nil,
+	}
+}
+
+// Error returns the message of the error, or the message of the cause if there is no message.
+func (e *Error) Error() string {
+	if len(e.Message) > 0 {
+		return e.Message
+	}
+
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return e.Kind.Error()
+}
+
+// Unwrap returns the kind and the cause of the error.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// ProtocolError means the server sent data that does not follow the protocol. Expected and Received
+// contain the values that were compared, if any.
+type ProtocolError struct {
+	Kind     error
+	Field    string
+	Expected any
+	Received any
+	Message  string
+}
+
+// NewProtocolError returns a protocol error with the expected and received values, which may be nil.
+func NewProtocolError(expected, received any, format string, args ...any) *ProtocolError {
+	return &ProtocolError{
+		Kind:     ErrProtocol,
+		Field:    "",
+		Expected: expected,
+		Received: received,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// NewUnexpectedPacket returns a protocol error for a packet with an unexpected type.
+func NewUnexpectedPacket(prefix string, expected, received int64) *ProtocolError {
+	return &ProtocolError{
+		Kind:     ErrUnexpectedPacket,
+		Field:    "packet type",
+		Expected: expected,
+		Received: received,
+		Message:  fmt.Sprintf("%s: received unexpected packet type (expected=0x%02X, received=0x%02X)", prefix, expected, received),
+	}
+}
+
+// NewPayloadMismatch returns a protocol error for a value that does not match the value sent to the server.
+func NewPayloadMismatch(prefix, field string, expected, received any) *ProtocolError {
+	return &ProtocolError{
+		Kind:     ErrPayloadMismatch,
+		Field:    field,
+		Expected: expected,
+		Received: received,
+		Message:  fmt.Sprintf("%s: %s mismatch (expected=%v, received=%v)", prefix, field, expected, received),
+	}
+}
+
+// NewOversize returns a protocol error for data that is larger than the limit.
+func NewOversize(prefix, field string, limit, received int64) *ProtocolError {
+	return &ProtocolError{
+		Kind:     ErrOversize,
+		Field:    field,
+		Expected: limit,
+		Received: received,
+		Message:  fmt.Sprintf("%s: %s is too large (limit=%d, received=%d)", prefix, field, limit, received),
+	}
+}
+
+// Error returns the message of the error.
+func (e *ProtocolError) Error() string {
+	return e.Message
+}
+
+// Is returns whether the target is ErrProtocol, which all protocol errors match.
+func (e *ProtocolError) Is(target error) bool {
+	return target == ErrProtocol
+}
+
+// Unwrap returns the kind of the error.
+func (e *ProtocolError) Unwrap() error {
+	return e.Kind
+}
+
+// Classify wraps DNS, connection refused and timeout errors into an *Error of the matching kind, keeping
+// the original error as the cause. Any other error, including errors that are already classified, is
+// returned as it is.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		classified *Error
+		protocol   *ProtocolError
+		dnsErr     *net.DNSError
+		netErr     net.Error
+		kind       error
+	)
+
+	switch {
+	case errors.As(err, &classified), errors.As(err, &protocol):
+		return err
+	case errors.As(err, &dnsErr):
+		kind = ErrDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		kind = ErrConnectionRefused
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		kind = ErrTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		kind = ErrTimeout
+	default:
+		return err
+	}
+
+	return &Error{
+		Kind:    kind,
+		Message: "",
+		Err:     err,
+	}
+}
@@ -0,0 +1,67 @@
+package mcerrors_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+)
+
+func TestClassify(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener.Close()
+
+	_, dialErr := net.Dial("tcp", listener.Addr().String())
+
+	tests := []struct {
+		err  error
+		kind error
+	}{
+		{&net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}, mcerrors.ErrDNS},
+		{dialErr, mcerrors.ErrConnectionRefused},
+		{os.ErrDeadlineExceeded, mcerrors.ErrTimeout},
+		{context.DeadlineExceeded, mcerrors.ErrTimeout},
+	}
+
+	for _, test := range tests {
+		err := mcerrors.Classify(test.err)
+
+		if !errors.Is(err, test.kind) {
+			t.Fatalf("expected %v to be classified as %v", test.err, test.kind)
+		}
+
+		if !errors.Is(err, test.err) {
+			t.Fatalf("expected classified error to wrap %v", test.err)
+		}
+	}
+
+	if err := mcerrors.Classify(context.Canceled); err != context.Canceled {
+		t.Fatalf("expected unclassified error to be returned as it is, received %v", err)
+	}
+}
+
+func TestProtocolError(t *testing.T) {
+	err := error(mcerrors.NewUnexpectedPacket("status", 0x00, 0x01))
+
+	if !errors.Is(err, mcerrors.ErrProtocol) || !errors.Is(err, mcerrors.ErrUnexpectedPacket) {
+		t.Fatalf("expected error to match ErrProtocol and ErrUnexpectedPacket: %v", err)
+	}
+
+	var protocolErr *mcerrors.ProtocolError
+
+	if !errors.As(err, &protocolErr) || protocolErr.Expected != int64(0x00) || protocolErr.Received != int64(0x01) {
+		t.Fatalf("unexpected protocol error values: %+v", protocolErr)
+	}
+
+	if err.Error() != "status: received unexpected packet type (expected=0x00, received=0x01)" {
+		t.Fatalf("unexpected error message: %s", err)
+	}
+}
@@ -6,4 +6,12 @@ import "time"
 type Query struct {
 	Timeout   time.Duration
 	SessionID int32
+	// Retry controls the retransmission of the handshake and stat requests that do not receive a response.
+	Retry Retry
+	// UnconnectedSocket accepts responses sent from any address instead of only the address the
+	// requests were sent to. Responses are matched by the session ID instead.
+	UnconnectedSocket bool
+	// Bedrock marks the server as a Bedrock Edition server, which is queried on the default Bedrock
+	// Edition port instead of the default Java Edition port when the port is 0.
+	Bedrock bool
 }
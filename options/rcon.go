@@ -5,4 +5,109 @@ import "time"
 // RCON is the options used when connecting using the RCON connection methods.
 type RCON struct {
 	Timeout time.Duration
+	// DisableReassembly stops the client from following each executed command with a sentinel request,
+	// so only the first response packet of a command is returned. This is only needed for servers that
+	// do not respond to unknown request types.
+	DisableReassembly bool
+	// OnDisconnect is called with the cause when the connection to the server is lost after logging in.
+	// It is not called when the connection is closed using Close().
+	OnDisconnect func(err error)
+}
+
+// RCONInFlightPolicy is what a resilient RCON client does with commands that were sent to the server but
+// did not receive a response before the connection was lost.
+type RCONInFlightPolicy int
+
+var (
+	// RCONInFlightFail returns an error for commands that were in-flight when the connection was lost,
+	// because the server may or may not have executed them.
+	RCONInFlightFail RCONInFlightPolicy = 0
+	// RCONInFlightReplay sends commands that were in-flight when the connection was lost again once the
+	// client has reconnected. This should only be used when all commands are safe to execute twice.
+	RCONInFlightReplay RCONInFlightPolicy = 1
+)
+
+// RCONResilient is the options used by the RCON client that automatically reconnects to the server.
+type RCONResilient struct {
+	// RCON is the options used for each connection to the server.
+	RCON RCON
+	// InitialBackoff is the delay before the first reconnect attempt, which defaults to 1 second.
+	InitialBackoff time.Duration
+	// MaxBackoff is the longest delay between reconnect attempts, which defaults to 30 seconds.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor the delay grows by after each failed attempt, which defaults to 2.
+	BackoffMultiplier float64
+	// MaxAttempts is the amount of reconnect attempts before the client gives up, or 0 to never give up.
+	MaxAttempts int
+	// InFlight is what happens to commands that were waiting for a response when the connection was lost.
+	InFlight RCONInFlightPolicy
+	// OnConnect is called after each successful reconnect and login.
+	OnConnect func()
+	// OnDisconnect is called with the cause when the connection to the server is lost.
+	OnDisconnect func(err error)
+	// OnReconnectFailed is called with the attempt number and the error when a reconnect attempt fails.
+	OnReconnectFailed func(attempt int, err error)
+}
+
+// RCONPool is the options used by the pool of RCON connections.
+type RCONPool struct {
+	// RCON is the options used for each connection in the pool.
+	RCON RCON
+	// Size is the amount of connections kept open to the server, which defaults to 4.
+	Size int
+	// MaxInFlight is the most commands that may wait for a response at once across all connections, which
+	// defaults to the size of the pool. Commands over the limit wait until another command has finished.
+	// Health checks count against the limit as well.
+	MaxInFlight int
+	// HealthCheckInterval is how long a connection must be idle before it is checked, which defaults to 30
+	// seconds. A negative value disables health checks.
+	HealthCheckInterval time.Duration
+	// HealthCheckCommand is the command executed to check idle connections. An empty command sends a packet
+	// that the server responds to without executing anything, which is not supported by all servers.
+	HealthCheckCommand string
+}
+
+// RCONServer is the options used by the RCON server.
+type RCONServer struct {
+	// FragmentSize is the largest payload of a single response packet, which defaults to 4096 bytes like
+	// the vanilla server. Longer responses are split across multiple packets.
+	FragmentSize int
+	// MaxConnections is the most connections the server accepts at once, or 0 for no limit.
+	MaxConnections int
+	// MaxLoginAttempts is the amount of failed logins after which a connection is closed, which defaults
+	// to 3.
+	MaxLoginAttempts int
+	// IdleTimeout is how long a connection may go without sending a packet before it is closed, or 0 for
+	// no limit.
+	IdleTimeout time.Duration
+	// MaxCommandLength is the longest command a connection may send before it is closed, or 0 for the
+	// largest payload that fits in a packet.
+	MaxCommandLength int
+}
+
+// RCONScheduler is the options used by the RCON command scheduler.
+type RCONScheduler struct {
+	// Timeout is how long each run of a job may take to execute its commands, which defaults to 30 seconds.
+	Timeout time.Duration
+	// StateFile is the path of the JSON file the last run of each job is saved to, so runs missed while the
+	// program was not running are detected when it starts again. An empty path keeps the state in memory.
+	StateFile string
+	// MaxCatchUp is the most missed runs of a single job that are executed when a job catches up on all
+	// missed runs, which defaults to 100. The most recent runs are kept.
+	MaxCatchUp int
+	// OnError is called with any error reading or writing the state file while the scheduler is running.
+	OnError func(err error)
+}
+
+// RCONBackup is the options used when backing up a server over RCON.
+type RCONBackup struct {
+	// StepTimeout is how long the save-off and save-on commands may take, which defaults to 10 seconds.
+	StepTimeout time.Duration
+	// SaveTimeout is how long the server may take to save the world, which defaults to 5 minutes.
+	SaveTimeout time.Duration
+	// BackupTimeout is how long the backup function may take, or 0 for no limit other than the context.
+	BackupTimeout time.Duration
+	// SaveConfirmation is the text the response to the save command must contain to confirm the world was
+	// saved, which defaults to "Saved the game" like the vanilla server.
+	SaveConfirmation string
 }
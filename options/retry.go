@@ -0,0 +1,15 @@
+package options
+
+import "time"
+
+// Retry is the options used to control the retransmission of UDP packets that do not receive a reply.
+type Retry struct {
+	// Attempts is the maximum amount of times a packet is sent. Any value less than 1 sends the packet once.
+	Attempts int
+	// AttemptTimeout is the amount of time to wait for a reply to the first attempt before the packet is
+	// sent again. If zero, the timeout is split across all attempts so the last one ends at the deadline.
+	AttemptTimeout time.Duration
+	// Backoff is the multiplier applied to the attempt timeout after each attempt. Any value less than 1
+	// uses a multiplier of 2.
+	Backoff float64
+}
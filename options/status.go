@@ -24,4 +24,30 @@ type StatusLegacy struct {
 type StatusBedrock struct {
 	Timeout    time.Duration
 	ClientGUID int64
+	// PingCount is the amount of unconnected pings sent to measure the latency of the server.
+	// Any value less than 1 sends a single ping.
+	PingCount int
+	// PingTimeout is the amount of time to wait for each pong before the ping is counted as lost.
+	// If zero, the Timeout is split evenly across all pings.
+	PingTimeout time.Duration
+	// Retry controls the retransmission of each unconnected ping that does not receive a pong.
+	Retry Retry
+	// UnconnectedSocket accepts pongs sent from any address instead of only the address the pings
+	// were sent to. Pongs are matched by the echoed ping time instead.
+	UnconnectedSocket bool
+}
+
+// Many is the options used by the status.Many() function.
+type Many struct {
+	// Concurrency is the maximum amount of statuses retrieved at the same time. Any value less than 1
+	// uses a concurrency of 16.
+	Concurrency int
+	// HostInterval is the minimum amount of time between the start of two requests to the same host.
+	HostInterval time.Duration
+	// Modern is the options used for Java Edition targets, or the default options if nil.
+	Modern *StatusModern
+	// Legacy is the options used for legacy Java Edition targets, or the default options if nil.
+	Legacy *StatusLegacy
+	// Bedrock is the options used for Bedrock Edition targets, or the default options if nil.
+	Bedrock *StatusBedrock
 }
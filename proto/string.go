@@ -2,8 +2,14 @@ package proto
 
 import (
 	"io"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 )
 
+// MaxStringLength is the largest length of a string that can be read, which is the
+// maximum size of a packet in the Minecraft protocol.
+const MaxStringLength = 2097151
+
 // ReadString reads a varint-prefixed string from the binary reader.
 func ReadString(r io.Reader) ([]byte, error) {
 	length, err := ReadVarInt(r)
@@ -12,6 +18,10 @@ func ReadString(r io.Reader) ([]byte, error) {
 		return nil, err
 	}
 
+	if length < 0 || length > MaxStringLength {
+		return nil, mcerrors.NewOversize("string", "length", MaxStringLength, int64(length))
+	}
+
 	data := make([]byte, length)
 
 	if _, err := io.ReadFull(r, data); err != nil {
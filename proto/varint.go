@@ -2,13 +2,18 @@ package proto
 
 import (
 	"encoding/binary"
-	"errors"
 	"io"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 )
 
 var (
 	// ErrVarIntTooBig means the varint received from the server is too big.
-	ErrVarIntTooBig = errors.New("varint: varint is too big")
+	ErrVarIntTooBig error = &mcerrors.ProtocolError{
+		Kind:    mcerrors.ErrOversize,
+		Field:   "varint",
+		Message: "varint: varint is too big",
+	}
 )
 
 // ReadVarInt reads a variable-length integer from the binary reader.
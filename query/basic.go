@@ -1,17 +1,17 @@
 package query
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/binary"
-	"fmt"
 	"io"
 	"net"
 	"strconv"
 	"time"
 
 	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/internal/udp"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/response"
 )
@@ -22,10 +22,10 @@ func Basic(ctx context.Context, hostname string, port uint16, options ...options
 	e := make(chan error, 1)
 
 	go func() {
-		result, err := performBasicQuery(hostname, port, options...)
+		result, err := performBasicQuery(ctx, hostname, port, options...)
 
 		if err != nil {
-			e <- err
+			e <- mcerrors.Classify(err)
 		} else if result != nil {
 			r <- result
 		}
@@ -34,10 +34,10 @@ func Basic(ctx context.Context, hostname string, port uint16, options ...options
 	select {
 	case <-ctx.Done():
 		if v := ctx.Err(); v != nil {
-			return nil, v
+			return nil, mcerrors.Classify(v)
 		}
 
-		return nil, context.DeadlineExceeded
+		return nil, mcerrors.Classify(context.DeadlineExceeded)
 	case v := <-r:
 		return v, nil
 	case v := <-e:
@@ -45,10 +45,10 @@ func Basic(ctx context.Context, hostname string, port uint16, options ...options
 	}
 }
 
-func performBasicQuery(hostname string, port uint16, options ...options.Query) (*response.QueryBasic, error) {
+func performBasicQuery(ctx context.Context, hostname string, port uint16, options ...options.Query) (*response.QueryBasic, error) {
 	opts := parseQueryOptions(options...)
 
-	conn, err := net.DialTimeout("udp", fmt.Sprintf("%s:%d", hostname, port), opts.Timeout)
+	conn, err := udp.Dial(hostname, queryPort(port, opts), opts.Timeout, opts.UnconnectedSocket)
 
 	if err != nil {
 		return nil, err
@@ -56,41 +56,67 @@ func performBasicQuery(hostname string, port uint16, options ...options.Query) (
 
 	defer conn.Close()
 
-	r := bufio.NewReader(conn)
+	deadline := time.Now().Add(opts.Timeout)
 
-	if err = conn.SetDeadline(time.Now().Add(opts.Timeout)); err != nil {
+	if err = conn.SetDeadline(deadline); err != nil {
 		return nil, err
 	}
 
-	// Handshake request packet
-	// https://wiki.vg/Query#Request
-	if err = writeHandshakeRequest(conn, opts.SessionID); err != nil {
-		return nil, err
-	}
-
-	// Handshake response packet
-	// https://wiki.vg/Query#Response
-	challengeToken, err := readHandshakeResponse(r, opts.SessionID)
+	// Handshake request and response packets
+	// https://wiki.vg/Query#Handshake
+	challengeToken, err := performHandshake(ctx, conn, deadline, opts)
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Basic stat request packet
-	// https://wiki.vg/Query#Request_2
-	if err = writeBasicStatRequest(conn, opts.SessionID, challengeToken); err != nil {
-		return nil, err
-	}
+	// Basic stat request and response packets
+	// https://wiki.vg/Query#Basic_stat
+	return performBasicStat(ctx, conn, deadline, opts, challengeToken)
+}
+
+func performBasicStat(ctx context.Context, conn *udp.Conn, deadline time.Time, opts options.Query, challengeToken int32) (*response.QueryBasic, error) {
+	var response *response.QueryBasic
+
+	err := udp.Exchange(
+		ctx,
+		conn,
+		deadline,
+		opts.Retry,
+		func(attempt int) ([]byte, error) {
+			buf := &bytes.Buffer{}
+
+			if err := writeBasicStatRequest(buf, opts.SessionID, challengeToken); err != nil {
+				return nil, err
+			}
+
+			return buf.Bytes(), nil
+		},
+		func(data []byte, addr net.Addr) (bool, error) {
+			// Duplicate handshake responses to retransmitted requests and late full stat responses
+			// to earlier requests on the same socket are ignored.
+			if !isResponse(data, 0x00, opts.SessionID) || isFullStatResponse(data) {
+				return false, nil
+			}
 
-	// Basic stat response packet
-	// https://wiki.vg/Query#Response_2
-	response, err := readBasicStatResponse(r, opts.SessionID)
+			result, err := readBasicStatResponse(data, opts.SessionID)
+
+			if err != nil {
+				return false, err
+			}
+
+			response = result
+			response.ReplyAddress = addr.String()
+
+			return true, nil
+		},
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return response, err
+	return response, nil
 }
 
 func writeBasicStatRequest(w io.Writer, sessionID int32, challengeToken int32) error {
@@ -123,30 +149,32 @@ func writeBasicStatRequest(w io.Writer, sessionID int32, challengeToken int32) e
 	return nil
 }
 
-func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic, error) {
+func readBasicStatResponse(data []byte, sessionID int32) (*response.QueryBasic, error) {
+	r := newDatagramReader(data)
+
 	// Type - byte
 	{
-		var packetType byte
+		packetType, err := r.readByte("packet type")
 
-		if err := binary.Read(r, binary.BigEndian, &packetType); err != nil {
+		if err != nil {
 			return nil, err
 		}
 
 		if packetType != 0x00 {
-			return nil, fmt.Errorf("query: received unexpected packet type (expected=0x00, received=0x%02X)", packetType)
+			return nil, mcerrors.NewUnexpectedPacket("query", 0x00, int64(packetType))
 		}
 	}
 
 	// Session ID - int32
 	{
-		var serverSessionID int32
+		serverSessionID, err := r.readInt32("session ID")
 
-		if err := binary.Read(r, binary.BigEndian, &serverSessionID); err != nil {
+		if err != nil {
 			return nil, err
 		}
 
 		if serverSessionID != sessionID {
-			return nil, fmt.Errorf("query: session ID mismatch (expected=%d, received=%d)", sessionID, serverSessionID)
+			return nil, mcerrors.NewPayloadMismatch("query", "session ID", sessionID, serverSessionID)
 		}
 	}
 
@@ -154,7 +182,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 
 	// MOTD - null-terminated string
 	{
-		value, err := readNTString(r)
+		value, err := r.readNTString("MOTD")
 
 		if err != nil {
 			return nil, err
@@ -171,7 +199,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 
 	// Game Type - null-terminated string
 	{
-		value, err := readNTString(r)
+		value, err := r.readNTString("game type")
 
 		if err != nil {
 			return nil, err
@@ -182,7 +210,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 
 	// Map - null-terminated string
 	{
-		value, err := readNTString(r)
+		value, err := r.readNTString("map")
 
 		if err != nil {
 			return nil, err
@@ -193,7 +221,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 
 	// Online Players - null-terminated string
 	{
-		value, err := readNTString(r)
+		value, err := r.readNTString("online players")
 
 		if err != nil {
 			return nil, err
@@ -202,7 +230,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 		onlinePlayers, err := strconv.ParseUint(value, 10, 64)
 
 		if err != nil {
-			return nil, err
+			return nil, mcerrors.NewProtocolError(nil, value, "query: invalid online players value: %q", value)
 		}
 
 		response.OnlinePlayers = onlinePlayers
@@ -210,7 +238,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 
 	// Max Players - null-terminated string
 	{
-		value, err := readNTString(r)
+		value, err := r.readNTString("max players")
 
 		if err != nil {
 			return nil, err
@@ -219,7 +247,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 		maxPlayers, err := strconv.ParseUint(value, 10, 64)
 
 		if err != nil {
-			return nil, err
+			return nil, mcerrors.NewProtocolError(nil, value, "query: invalid max players value: %q", value)
 		}
 
 		response.MaxPlayers = maxPlayers
@@ -227,9 +255,9 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 
 	// Host Port - uint16
 	{
-		var value uint16
+		value, err := r.readUint16LE("host port")
 
-		if err := binary.Read(r, binary.LittleEndian, &value); err != nil {
+		if err != nil {
 			return nil, err
 		}
 
@@ -238,7 +266,7 @@ func readBasicStatResponse(r io.Reader, sessionID int32) (*response.QueryBasic,
 
 	// Host IP - null-terminated string
 	{
-		value, err := readNTString(r)
+		value, err := r.readNTString("host IP")
 
 		if err != nil {
 			return nil, err
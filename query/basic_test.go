@@ -2,8 +2,12 @@ package query_test
 
 import (
 	"context"
+	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/query"
 	"github.com/mcstatus-io/mcutil/v4/util"
 )
@@ -17,3 +21,53 @@ func TestBasic(t *testing.T) {
 
 	t.Logf("%+v\n", resp)
 }
+
+func TestBasicContext(t *testing.T) {
+	// A socket that never responds counts the retransmitted handshake requests.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	var received atomic.Int32
+
+	go (func() {
+		data := make([]byte, 1500)
+
+		for {
+			if _, _, err := conn.ReadFrom(data); err != nil {
+				return
+			}
+
+			received.Add(1)
+		}
+	})()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+
+	defer cancel()
+
+	if _, err := query.Basic(ctx, "127.0.0.1", uint16(conn.LocalAddr().(*net.UDPAddr).Port), options.Query{
+		Timeout: time.Second * 5,
+		Retry: options.Retry{
+			Attempts:       50,
+			AttemptTimeout: time.Millisecond * 50,
+			Backoff:        1,
+		},
+	}); err == nil {
+		t.Fatal("expected the query to fail")
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	count := received.Load()
+
+	time.Sleep(time.Millisecond * 300)
+
+	if received.Load() != count {
+		t.Fatal("expected no requests to be sent after the context was done")
+	}
+}
@@ -0,0 +1,171 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/internal/udp"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+// ChallengeTokenCacheDuration is the amount of time a client reuses a challenge token for. The vanilla server
+// expires all challenge tokens every 30 seconds regardless of when they were issued, so a cached token may
+// still go stale early, in which case the request is retried with a new token after a short wait.
+const ChallengeTokenCacheDuration = time.Second * 25
+
+// minStaleTokenWait is the shortest time spent waiting for the response to a request using a cached token
+// before assuming that the token is stale.
+const minStaleTokenWait = time.Millisecond * 100
+
+var (
+	// ErrClientClosed means a request was made using a client that has been closed.
+	ErrClientClosed = errors.New("query: client is closed")
+)
+
+// Client is a query client bound to a single server. The client reuses one socket for all requests and
+// caches the challenge token between them, so most requests only need a single round trip. All methods
+// are safe to call from multiple goroutines, and requests are sent one at a time.
+type Client struct {
+	conn           *udp.Conn
+	opts           options.Query
+	lock           chan struct{}
+	closed         chan struct{}
+	challengeToken int32
+	tokenExpiresAt time.Time
+	handshakeRTT   time.Duration
+}
+
+// NewClient opens a socket to the server and returns a new client. No packets are sent until the first request.
+func NewClient(hostname string, port uint16, options ...options.Query) (*Client, error) {
+	opts := parseQueryOptions(options...)
+
+	conn, err := udp.Dial(hostname, queryPort(port, opts), opts.Timeout, opts.UnconnectedSocket)
+
+	if err != nil {
+		return nil, mcerrors.Classify(err)
+	}
+
+	return &Client{
+		conn:           conn,
+		opts:           opts,
+		lock:           make(chan struct{}, 1),
+		closed:         make(chan struct{}),
+		challengeToken: 0,
+		tokenExpiresAt: time.Time{},
+		handshakeRTT:   0,
+	}, nil
+}
+
+// Basic runs a basic query on the server.
+func (c *Client) Basic(ctx context.Context) (*response.QueryBasic, error) {
+	var result *response.QueryBasic
+
+	err := c.request(ctx, func(deadline time.Time, challengeToken int32) (err error) {
+		result, err = performBasicStat(ctx, c.conn, deadline, c.opts, challengeToken)
+
+		return err
+	})
+
+	return result, err
+}
+
+// Full runs a full query on the server.
+func (c *Client) Full(ctx context.Context) (*response.QueryFull, error) {
+	var result *response.QueryFull
+
+	err := c.request(ctx, func(deadline time.Time, challengeToken int32) (err error) {
+		result, err = performFullStat(ctx, c.conn, deadline, c.opts, challengeToken)
+
+		return err
+	})
+
+	return result, err
+}
+
+// Close closes the socket of the client. Any request waiting to be sent fails with ErrClientClosed.
+func (c *Client) Close() error {
+	select {
+	case <-c.closed:
+		return nil
+	default:
+		close(c.closed)
+	}
+
+	return c.conn.Close()
+}
+
+// request runs the stat request with a valid challenge token. If the request fails using a cached
+// token, the token is refreshed and the request is tried once more.
+func (c *Client) request(ctx context.Context, stat func(deadline time.Time, challengeToken int32) error) error {
+	select {
+	case c.lock <- struct{}{}:
+		defer func() { <-c.lock }()
+	case <-c.closed:
+		return ErrClientClosed
+	case <-ctx.Done():
+		return mcerrors.Classify(ctx.Err())
+	}
+
+	select {
+	case <-c.closed:
+		return ErrClientClosed
+	default:
+	}
+
+	deadline := time.Now().Add(c.opts.Timeout)
+
+	if v, ok := ctx.Deadline(); ok && v.Before(deadline) {
+		deadline = v
+	}
+
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	if time.Now().Before(c.tokenExpiresAt) {
+		// A stale token is never answered, so the cached token is only given a few round trips to be
+		// answered before it is refreshed, and never more than half of the time.
+		wait := min(max(c.handshakeRTT*4, minStaleTokenWait), time.Until(deadline)/2)
+
+		err := stat(time.Now().Add(wait), c.challengeToken)
+
+		if err == nil || ctx.Err() != nil {
+			return mcerrors.Classify(err)
+		}
+
+		c.tokenExpiresAt = time.Time{}
+	}
+
+	if err := c.refreshChallengeToken(ctx, deadline); err != nil {
+		return mcerrors.Classify(err)
+	}
+
+	if err := stat(deadline, c.challengeToken); err != nil {
+		c.tokenExpiresAt = time.Time{}
+
+		return mcerrors.Classify(err)
+	}
+
+	return nil
+}
+
+func (c *Client) refreshChallengeToken(ctx context.Context, deadline time.Time) error {
+	issuedAt := time.Now()
+
+	// Handshake request and response packets
+	// https://wiki.vg/Query#Handshake
+	challengeToken, err := performHandshake(ctx, c.conn, deadline, c.opts)
+
+	if err != nil {
+		return err
+	}
+
+	c.challengeToken = challengeToken
+	c.tokenExpiresAt = issuedAt.Add(ChallengeTokenCacheDuration)
+	c.handshakeRTT = time.Since(issuedAt)
+
+	return nil
+}
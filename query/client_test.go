@@ -0,0 +1,139 @@
+package query_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/query"
+)
+
+// handshakeCountingConn counts the handshake requests received by a server.
+type handshakeCountingConn struct {
+	net.PacketConn
+	handshakes atomic.Int32
+}
+
+func (c *handshakeCountingConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(p)
+
+	if err == nil && n >= 3 && p[2] == 0x09 {
+		c.handshakes.Add(1)
+	}
+
+	return n, addr, err
+}
+
+func TestClient(t *testing.T) {
+	server, err := query.NewServer(testProvider{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := &handshakeCountingConn{PacketConn: packetConn}
+
+	go server.Serve(conn)
+
+	defer server.Close()
+
+	client, err := query.NewClient("127.0.0.1", uint16(packetConn.LocalAddr().(*net.UDPAddr).Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			if i%2 == 0 {
+				resp, err := client.Basic(context.Background())
+
+				if err != nil {
+					t.Error(err)
+				} else if resp.GameType != "SMP" {
+					t.Errorf("unexpected basic response: %+v", resp)
+				}
+
+				return
+			}
+
+			resp, err := client.Full(context.Background())
+
+			if err != nil {
+				t.Error(err)
+			} else if len(resp.Players) != 2 {
+				t.Errorf("unexpected full response: %+v", resp)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if v := conn.handshakes.Load(); v != 1 {
+		t.Fatalf("expected the challenge token to be reused (handshakes=%d)", v)
+	}
+}
+
+func TestClientStaleChallengeToken(t *testing.T) {
+	server, port := startTestServer(t)
+
+	client, err := query.NewClient("127.0.0.1", port, options.Query{Timeout: time.Second * 5})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	if _, err := client.Basic(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new server on the same port uses a different secret, so the cached challenge token is stale.
+	server.Close()
+
+	restarted, err := query.NewServer(testProvider{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.ListenPacket("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go restarted.Serve(conn)
+
+	defer restarted.Close()
+
+	start := time.Now()
+
+	if _, err := client.Basic(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the stale challenge token to be refreshed quickly, took %s", elapsed)
+	}
+}
@@ -1,7 +1,6 @@
 package query
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/binary"
@@ -9,8 +8,13 @@ import (
 	"io"
 	"math/rand"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/internal/udp"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/response"
 )
@@ -21,10 +25,10 @@ func Full(ctx context.Context, hostname string, port uint16, options ...options.
 	e := make(chan error, 1)
 
 	go func() {
-		result, err := performFullQuery(hostname, port, options...)
+		result, err := performFullQuery(ctx, hostname, port, options...)
 
 		if err != nil {
-			e <- err
+			e <- mcerrors.Classify(err)
 		} else if result != nil {
 			r <- result
 		}
@@ -33,10 +37,10 @@ func Full(ctx context.Context, hostname string, port uint16, options ...options.
 	select {
 	case <-ctx.Done():
 		if v := ctx.Err(); v != nil {
-			return nil, v
+			return nil, mcerrors.Classify(v)
 		}
 
-		return nil, context.DeadlineExceeded
+		return nil, mcerrors.Classify(context.DeadlineExceeded)
 	case v := <-r:
 		return v, nil
 	case v := <-e:
@@ -44,10 +48,10 @@ func Full(ctx context.Context, hostname string, port uint16, options ...options.
 	}
 }
 
-func performFullQuery(hostname string, port uint16, options ...options.Query) (*response.QueryFull, error) {
+func performFullQuery(ctx context.Context, hostname string, port uint16, options ...options.Query) (*response.QueryFull, error) {
 	opts := parseQueryOptions(options...)
 
-	conn, err := net.DialTimeout("udp", fmt.Sprintf("%s:%d", hostname, port), opts.Timeout)
+	conn, err := udp.Dial(hostname, queryPort(port, opts), opts.Timeout, opts.UnconnectedSocket)
 
 	if err != nil {
 		return nil, err
@@ -55,41 +59,67 @@ func performFullQuery(hostname string, port uint16, options ...options.Query) (*
 
 	defer conn.Close()
 
-	r := bufio.NewReader(conn)
+	deadline := time.Now().Add(opts.Timeout)
 
-	if err = conn.SetDeadline(time.Now().Add(opts.Timeout)); err != nil {
+	if err = conn.SetDeadline(deadline); err != nil {
 		return nil, err
 	}
 
-	// Handshake request packet
-	// https://wiki.vg/Query#Request
-	if err = writeHandshakeRequest(conn, opts.SessionID); err != nil {
-		return nil, err
-	}
-
-	// Handshake response packet
-	// https://wiki.vg/Query#Response
-	challengeToken, err := readHandshakeResponse(r, opts.SessionID)
+	// Handshake request and response packets
+	// https://wiki.vg/Query#Handshake
+	challengeToken, err := performHandshake(ctx, conn, deadline, opts)
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Full stat request packet
-	// https://wiki.vg/Query#Request_3
-	if err = writeFullStatRequest(conn, opts.SessionID, challengeToken); err != nil {
-		return nil, err
-	}
+	// Full stat request and response packets
+	// https://wiki.vg/Query#Full_stat
+	return performFullStat(ctx, conn, deadline, opts, challengeToken)
+}
 
-	// Full stat response packet
-	// https://wiki.vg/Query#Response_3
-	response, err := readFullStatResponse(r, opts.SessionID)
+func performFullStat(ctx context.Context, conn *udp.Conn, deadline time.Time, opts options.Query, challengeToken int32) (*response.QueryFull, error) {
+	var response *response.QueryFull
+
+	err := udp.Exchange(
+		ctx,
+		conn,
+		deadline,
+		opts.Retry,
+		func(attempt int) ([]byte, error) {
+			buf := &bytes.Buffer{}
+
+			if err := writeFullStatRequest(buf, opts.SessionID, challengeToken); err != nil {
+				return nil, err
+			}
+
+			return buf.Bytes(), nil
+		},
+		func(data []byte, addr net.Addr) (bool, error) {
+			// Duplicate handshake responses to retransmitted requests and late basic stat responses
+			// to earlier requests on the same socket are ignored.
+			if !isResponse(data, 0x00, opts.SessionID) || !isFullStatResponse(data) {
+				return false, nil
+			}
+
+			result, err := readFullStatResponse(data, opts.SessionID)
+
+			if err != nil {
+				return false, err
+			}
+
+			response = result
+			response.ReplyAddress = addr.String()
+
+			return true, nil
+		},
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return response, err
+	return response, nil
 }
 
 func writeFullStatRequest(w io.Writer, sessionID int32, challengeToken int32) error {
@@ -127,51 +157,53 @@ func writeFullStatRequest(w io.Writer, sessionID int32, challengeToken int32) er
 	return nil
 }
 
-func readFullStatResponse(r io.Reader, sessionID int32) (*response.QueryFull, error) {
+func readFullStatResponse(data []byte, sessionID int32) (*response.QueryFull, error) {
+	r := newDatagramReader(data)
+
 	// Type - byte
 	{
-		var packetType byte
+		packetType, err := r.readByte("packet type")
 
-		if err := binary.Read(r, binary.BigEndian, &packetType); err != nil {
+		if err != nil {
 			return nil, err
 		}
 
 		if packetType != 0x00 {
-			return nil, fmt.Errorf("query: received unexpected packet type (expected=0x00, received=0x%02X)", packetType)
+			return nil, mcerrors.NewUnexpectedPacket("query", 0x00, int64(packetType))
 		}
 	}
 
-	// Session ID - int16
+	// Session ID - int32
 	{
-		var serverSessionID int32
+		serverSessionID, err := r.readInt32("session ID")
 
-		if err := binary.Read(r, binary.BigEndian, &serverSessionID); err != nil {
+		if err != nil {
 			return nil, err
 		}
 
 		if serverSessionID != sessionID {
-			return nil, fmt.Errorf("query: session ID mismatch (expected=%d, received=%d)", sessionID, serverSessionID)
+			return nil, mcerrors.NewPayloadMismatch("query", "session ID", sessionID, serverSessionID)
 		}
 	}
 
 	// Padding - [11]byte
-	{
-		data := make([]byte, 11)
-
-		if _, err := r.Read(data); err != nil {
-			return nil, err
-		}
+	if err := r.skip("padding", len(fullStatPadding)); err != nil {
+		return nil, err
 	}
 
 	response := response.QueryFull{
+		Plugins: ParsePlugins(""),
+		Extra:   make(map[string]string),
 		Data:    make(map[string]string),
 		Players: make([]string, 0),
 	}
 
 	// K, V section - null-terminated key,pair pair string
 	{
-		for {
-			key, err := readNTString(r)
+		// The section ends with an empty key, although the end of the datagram is also
+		// accepted since some servers leave out everything after the last value.
+		for r.remaining() > 0 {
+			key, err := r.readNTString("key")
 
 			if err != nil {
 				return nil, err
@@ -181,7 +213,7 @@ func readFullStatResponse(r io.Reader, sessionID int32) (*response.QueryFull, er
 				break
 			}
 
-			value, err := readNTString(r)
+			value, err := r.readNTString(fmt.Sprintf("value of key %q", key))
 
 			if err != nil {
 				return nil, err
@@ -192,18 +224,17 @@ func readFullStatResponse(r io.Reader, sessionID int32) (*response.QueryFull, er
 	}
 
 	// Padding - [10]byte
+	// Players section - null-terminated key,value pair string
 	{
-		data := make([]byte, 10)
-
-		if _, err := r.Read(data); err != nil {
-			return nil, err
+		// Some servers do not send the player section at all.
+		if r.remaining() > 0 {
+			if err := r.skip("players padding", len(playersPadding)); err != nil {
+				return nil, err
+			}
 		}
-	}
 
-	// Players section - null-terminated key,value pair string
-	{
-		for {
-			username, err := readNTString(r)
+		for r.remaining() > 0 {
+			username, err := r.readNTString("player name")
 
 			if err != nil {
 				return nil, err
@@ -217,9 +248,104 @@ func readFullStatResponse(r io.Reader, sessionID int32) (*response.QueryFull, er
 		}
 	}
 
+	if err := parseFullStatData(&response); err != nil {
+		return nil, err
+	}
+
 	return &response, nil
 }
 
+// parseFullStatData parses the typed fields of the response from the key/value data.
+func parseFullStatData(result *response.QueryFull) error {
+	for key, value := range result.Data {
+		switch key {
+		case "hostname":
+			{
+				motd, err := formatting.Parse(value)
+
+				if err != nil {
+					return err
+				}
+
+				result.MOTD = *motd
+			}
+		case "gametype":
+			result.GameType = value
+		case "game_id":
+			result.GameID = value
+		case "version":
+			result.Version = value
+		case "plugins":
+			result.Plugins = ParsePlugins(value)
+		case "map":
+			result.Map = value
+		case "numplayers", "maxplayers":
+			{
+				if len(value) < 1 {
+					continue
+				}
+
+				players, err := strconv.ParseUint(value, 10, 64)
+
+				if err != nil {
+					return mcerrors.NewProtocolError(nil, value, "query: invalid %s value: %s", key, value)
+				}
+
+				if key == "numplayers" {
+					result.OnlinePlayers = players
+				} else {
+					result.MaxPlayers = players
+				}
+			}
+		case "hostport":
+			{
+				if len(value) < 1 {
+					continue
+				}
+
+				port, err := strconv.ParseUint(value, 10, 16)
+
+				if err != nil {
+					return mcerrors.NewProtocolError(nil, value, "query: invalid hostport value: %s", value)
+				}
+
+				result.HostPort = uint16(port)
+			}
+		case "hostip":
+			result.HostIP = value
+		case "server_engine":
+			result.ServerEngine = value
+		case "whitelist":
+			{
+				switch strings.ToLower(value) {
+				case "on", "true", "1":
+					result.Whitelist = pointerOf(true)
+				case "off", "false", "0":
+					result.Whitelist = pointerOf(false)
+				default:
+					result.Extra[key] = value
+				}
+			}
+		default:
+			result.Extra[key] = value
+		}
+	}
+
+	// Bedrock Edition servers report a different game ID, and some of them do not send any plugins
+	// value but report the server software in the "server_engine" key instead.
+	result.Edition = response.QueryEditionJava
+
+	if strings.EqualFold(result.GameID, "MINECRAFTPE") {
+		result.Edition = response.QueryEditionBedrock
+	}
+
+	if len(result.Plugins.Software) < 1 && len(result.ServerEngine) > 0 {
+		result.Plugins.Software, result.Plugins.SoftwareVersion = splitVersion(result.ServerEngine)
+	}
+
+	return nil
+}
+
 func parseQueryOptions(opts ...options.Query) options.Query {
 	if len(opts) < 1 {
 		options := options.Query(defaultQueryOptions)
@@ -1,10 +1,18 @@
 package query_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"errors"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/query"
+	"github.com/mcstatus-io/mcutil/v4/response"
 	"github.com/mcstatus-io/mcutil/v4/util"
 )
 
@@ -17,3 +25,111 @@ func TestFull(t *testing.T) {
 
 	t.Logf("%+v\n", resp)
 }
+
+func TestFullMissingPlayerSection(t *testing.T) {
+	body := []byte("splitnum\x00\x80\x00hostname\x00A \xA7aServer\x00numplayers\x001\x00\x00")
+
+	resp, err := runRawFullQuery(t, body)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.MOTD.Clean != "A Server" || resp.OnlinePlayers != 1 || len(resp.Players) != 0 {
+		t.Fatalf("unexpected full response: %+v", resp)
+	}
+}
+
+func TestFullPlayerEncoding(t *testing.T) {
+	body := []byte("splitnum\x00\x80\x00hostname\x00Server\x00\x00\x01player_\x00\x00J\xF6rg\x00Bj\xC3\xB6rn\x00\x00")
+
+	resp, err := runRawFullQuery(t, body)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Players) != 2 || resp.Players[0] != "Jörg" || resp.Players[1] != "Björn" {
+		t.Fatalf("unexpected players: %q", resp.Players)
+	}
+}
+
+func TestFullBedrock(t *testing.T) {
+	body := []byte("splitnum\x00\x80\x00hostname\x00Bedrock Server\x00gametype\x00SMP\x00game_id\x00MINECRAFTPE\x00version\x001.21.0\x00server_engine\x00PocketMine-MP 5.18.0\x00plugins\x00\x00map\x00world\x00numplayers\x000\x00maxplayers\x0020\x00whitelist\x00off\x00hostip\x000.0.0.0\x00hostport\x0019132\x00\x00\x01player_\x00\x00\x00")
+
+	resp, err := runRawFullQuery(t, body)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Edition != response.QueryEditionBedrock {
+		t.Fatalf("expected the Bedrock edition, received %q", resp.Edition)
+	}
+
+	if resp.ServerEngine != "PocketMine-MP 5.18.0" || resp.Plugins.Software != "PocketMine-MP" || resp.Plugins.SoftwareVersion != "5.18.0" {
+		t.Fatalf("unexpected server software: %q %+v", resp.ServerEngine, resp.Plugins)
+	}
+
+	if resp.Whitelist == nil || *resp.Whitelist {
+		t.Fatalf("expected the whitelist to be disabled, received %v", resp.Whitelist)
+	}
+
+	if _, ok := resp.Extra["whitelist"]; ok {
+		t.Fatal("expected the whitelist key to be removed from the extra data")
+	}
+}
+
+func TestFullTruncated(t *testing.T) {
+	body := []byte("splitnum\x00\x80\x00hostname\x00Ser")
+
+	_, err := runRawFullQuery(t, body)
+
+	if !errors.Is(err, mcerrors.ErrProtocol) {
+		t.Fatalf("expected a protocol error, received %v", err)
+	}
+}
+
+// runRawFullQuery runs a full query against a server that responds to the stat request with the body.
+func runRawFullQuery(t *testing.T, body []byte) (*response.QueryFull, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	go func() {
+		data := make([]byte, 1500)
+
+		for {
+			n, addr, err := conn.ReadFrom(data)
+
+			if err != nil {
+				return
+			}
+
+			if n < 7 {
+				continue
+			}
+
+			buf := &bytes.Buffer{}
+			buf.WriteByte(data[2])
+			buf.Write(data[3:7])
+
+			if data[2] == 0x09 {
+				buf.WriteString("9513307\x00")
+			} else {
+				buf.Write(body)
+			}
+
+			conn.WriteTo(buf.Bytes(), addr)
+		}
+	}()
+
+	return query.Full(context.Background(), "127.0.0.1", uint16(conn.LocalAddr().(*net.UDPAddr).Port), options.Query{
+		Timeout:   time.Second,
+		SessionID: int32(binary.BigEndian.Uint32([]byte{0x01, 0x02, 0x03, 0x04})),
+	})
+}
@@ -0,0 +1,138 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+// ParsePlugins parses the "plugins" value of a full query into the server software and plugin list. Values
+// that do not follow the Bukkit format are parsed as far as possible, and the raw value is always kept.
+func ParsePlugins(value string) response.QueryPlugins {
+	result := response.QueryPlugins{
+		Raw:             value,
+		Software:        "",
+		SoftwareVersion: "",
+		Platform:        "",
+		Plugins:         make([]response.QueryPlugin, 0),
+		Hidden:          false,
+	}
+
+	value = strings.TrimSpace(value)
+
+	if len(value) < 1 {
+		return result
+	}
+
+	software, plugins, found := strings.Cut(value, ":")
+
+	// Bukkit only reports the server software when the plugin list is hidden using the
+	// "query-plugins" setting.
+	result.Hidden = !found
+
+	// Server software - "<software> on <platform> <version>" or "<software> <version>"
+	{
+		software = strings.TrimSpace(software)
+
+		if name, platform, ok := strings.Cut(software, " on "); ok {
+			result.Software = strings.TrimSpace(name)
+			result.Platform, result.SoftwareVersion = splitVersion(platform)
+		} else {
+			result.Software, result.SoftwareVersion = splitVersion(software)
+		}
+	}
+
+	// Plugin list - "<plugin> <version>; <plugin> <version>; ..."
+	{
+		// Some servers separate plugins with new lines or commas instead of semicolons.
+		separator := ";"
+
+		if !strings.Contains(plugins, ";") && !strings.Contains(plugins, "\n") && strings.Contains(plugins, ",") {
+			separator = ","
+		}
+
+		for _, entry := range strings.Split(strings.ReplaceAll(plugins, "\n", separator), separator) {
+			entry = strings.TrimSpace(entry)
+
+			if len(entry) < 1 {
+				continue
+			}
+
+			name, version := splitVersion(entry)
+
+			if len(name) < 1 {
+				name, version = version, ""
+			}
+
+			result.Plugins = append(result.Plugins, response.QueryPlugin{
+				Name:    name,
+				Version: version,
+			})
+		}
+	}
+
+	return result
+}
+
+// FormatPlugins returns the "plugins" value of a full query for the server software and plugin list. The
+// raw value is returned as it is if it is not empty.
+func FormatPlugins(plugins response.QueryPlugins) string {
+	if len(plugins.Raw) > 0 {
+		return plugins.Raw
+	}
+
+	if len(plugins.Software) < 1 {
+		return ""
+	}
+
+	result := plugins.Software
+
+	if len(plugins.Platform) > 0 {
+		result += " on " + plugins.Platform
+	}
+
+	if len(plugins.SoftwareVersion) > 0 {
+		result += " " + plugins.SoftwareVersion
+	}
+
+	if plugins.Hidden {
+		return result
+	}
+
+	entries := make([]string, 0, len(plugins.Plugins))
+
+	for _, plugin := range plugins.Plugins {
+		entries = append(entries, strings.TrimSpace(plugin.Name+" "+plugin.Version))
+	}
+
+	return result + ": " + strings.Join(entries, "; ")
+}
+
+// splitVersion splits the last word off of the value if it looks like a version.
+func splitVersion(value string) (string, string) {
+	value = strings.Join(strings.Fields(value), " ")
+
+	index := strings.LastIndex(value, " ")
+
+	if index < 0 {
+		if isVersion(value) {
+			return "", value
+		}
+
+		return value, ""
+	}
+
+	if version := value[index+1:]; isVersion(version) {
+		return value[:index], strings.Trim(version, "()")
+	}
+
+	return value, ""
+}
+
+// isVersion returns whether the value looks like a version, such as "1.20.4", "v7.2.15" or "(2.0)".
+func isVersion(value string) bool {
+	value = strings.TrimLeft(value, "(vV")
+
+	return len(value) > 0 && unicode.IsDigit(rune(value[0]))
+}
@@ -0,0 +1,85 @@
+package query_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcstatus-io/mcutil/v4/query"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+func TestParsePlugins(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected response.QueryPlugins
+	}{
+		{
+			value: "CraftBukkit on Bukkit 1.20.4-R0.1-SNAPSHOT: WorldEdit 7.2.15; Vault 1.7.3-b131;",
+			expected: response.QueryPlugins{
+				Software:        "CraftBukkit",
+				SoftwareVersion: "1.20.4-R0.1-SNAPSHOT",
+				Platform:        "Bukkit",
+				Plugins: []response.QueryPlugin{
+					{Name: "WorldEdit", Version: "7.2.15"},
+					{Name: "Vault", Version: "1.7.3-b131"},
+				},
+			},
+		},
+		{
+			value: "Paper on Bukkit 1.20.4",
+			expected: response.QueryPlugins{
+				Software:        "Paper",
+				SoftwareVersion: "1.20.4",
+				Platform:        "Bukkit",
+				Plugins:         []response.QueryPlugin{},
+				Hidden:          true,
+			},
+		},
+		{
+			value: "PocketMine-MP 5.10.0: DevTools v1.14.0\nEconomyAPI 5.7.2",
+			expected: response.QueryPlugins{
+				Software:        "PocketMine-MP",
+				SoftwareVersion: "5.10.0",
+				Plugins: []response.QueryPlugin{
+					{Name: "DevTools", Version: "v1.14.0"},
+					{Name: "EconomyAPI", Version: "5.7.2"},
+				},
+			},
+		},
+		{
+			value: "Spigot:  Custom Plugin ;;Essentials (2.20.1)",
+			expected: response.QueryPlugins{
+				Software: "Spigot",
+				Plugins: []response.QueryPlugin{
+					{Name: "Custom Plugin", Version: ""},
+					{Name: "Essentials", Version: "2.20.1"},
+				},
+			},
+		},
+		{
+			value: "",
+			expected: response.QueryPlugins{
+				Plugins: []response.QueryPlugin{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		result := query.ParsePlugins(test.value)
+		test.expected.Raw = test.value
+
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Fatalf("unexpected result for %q:\nexpected=%+v\nreceived=%+v", test.value, test.expected, result)
+		}
+	}
+}
+
+func TestFormatPlugins(t *testing.T) {
+	value := "CraftBukkit on Bukkit 1.20.4-R0.1-SNAPSHOT: WorldEdit 7.2.15; Vault 1.7.3"
+	plugins := query.ParsePlugins(value)
+	plugins.Raw = ""
+
+	if result := query.FormatPlugins(plugins); result != value {
+		t.Fatalf("unexpected formatted plugins (expected=%q, received=%q)", value, result)
+	}
+}
@@ -0,0 +1,94 @@
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/mcstatus-io/mcutil/v4/internal/charset"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+)
+
+// datagramReader reads the fields of a single response datagram. Every read is bounds checked, so a
+// truncated datagram results in an error naming the field that could not be read.
+type datagramReader struct {
+	data   []byte
+	offset int
+}
+
+func newDatagramReader(data []byte) *datagramReader {
+	return &datagramReader{
+		data:   data,
+		offset: 0,
+	}
+}
+
+// remaining returns the amount of unread bytes.
+func (r *datagramReader) remaining() int {
+	return len(r.data) - r.offset
+}
+
+func (r *datagramReader) readByte(field string) (byte, error) {
+	if r.remaining() < 1 {
+		return 0, r.truncated(field, 1)
+	}
+
+	value := r.data[r.offset]
+
+	r.offset++
+
+	return value, nil
+}
+
+func (r *datagramReader) readInt32(field string) (int32, error) {
+	if r.remaining() < 4 {
+		return 0, r.truncated(field, 4)
+	}
+
+	value := int32(binary.BigEndian.Uint32(r.data[r.offset:]))
+
+	r.offset += 4
+
+	return value, nil
+}
+
+func (r *datagramReader) readUint16LE(field string) (uint16, error) {
+	if r.remaining() < 2 {
+		return 0, r.truncated(field, 2)
+	}
+
+	value := binary.LittleEndian.Uint16(r.data[r.offset:])
+
+	r.offset += 2
+
+	return value, nil
+}
+
+func (r *datagramReader) skip(field string, length int) error {
+	if r.remaining() < length {
+		return r.truncated(field, length)
+	}
+
+	r.offset += length
+
+	return nil
+}
+
+// readNTString reads a null-terminated string. The string is decoded as UTF-8 if it is valid UTF-8,
+// otherwise as ISO-8859-1 which is used by older servers.
+func (r *datagramReader) readNTString(field string) (string, error) {
+	index := bytes.IndexByte(r.data[r.offset:], 0x00)
+
+	if index < 0 {
+		return "", mcerrors.NewProtocolError(nil, nil, "query: missing null terminator of %s (offset=%d, length=%d)", field, r.offset, len(r.data))
+	}
+
+	value := charset.Decode(r.data[r.offset : r.offset+index])
+
+	r.offset += index + 1
+
+	return value, nil
+}
+
+func (r *datagramReader) truncated(field string, length int) error {
+	return mcerrors.NewProtocolError(length, r.remaining(), "query: response is too short to read %s (offset=%d, expected=%d, received=%d)", field, r.offset, length, r.remaining())
+}
@@ -0,0 +1,426 @@
+package query
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/internal/udp"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+// ChallengeTokenLifetime is the amount of time after which the challenge tokens issued by a server rotate.
+// A token remains valid until the end of the rotation after the one it was issued in.
+const ChallengeTokenLifetime = time.Second * 30
+
+var (
+	fullStatPadding  = []byte{0x73, 0x70, 0x6C, 0x69, 0x74, 0x6E, 0x75, 0x6D, 0x00, 0x80, 0x00}
+	playersPadding   = []byte{0x01, 0x70, 0x6C, 0x61, 0x79, 0x65, 0x72, 0x5F, 0x00, 0x00}
+	fullStatKeyOrder = []string{"hostname", "gametype", "game_id", "version", "plugins", "map", "numplayers", "maxplayers", "hostport", "hostip"}
+)
+
+var (
+	// ErrServerClosed is returned by the Serve() and ListenAndServe() methods of a server after it has been closed.
+	ErrServerClosed = errors.New("query: server closed")
+)
+
+// Provider provides the data that a query server responds with. The methods are called for
+// every stat request, and may be called from multiple goroutines at the same time. Returning a
+// nil result without an error leaves the request unanswered.
+type Provider interface {
+	QueryBasic() (*response.QueryBasic, error)
+	QueryFull() (*response.QueryFull, error)
+}
+
+// Server is a server that responds to the query protocol using the data of a provider.
+type Server struct {
+	provider Provider
+	secret   []byte
+	conn     net.PacketConn
+	closed   bool
+	mutex    sync.Mutex
+}
+
+// NewServer creates a new query server that responds with the data of the provider.
+func NewServer(provider Provider) (*Server, error) {
+	secret := make([]byte, 32)
+
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		provider: provider,
+		secret:   secret,
+		conn:     nil,
+		closed:   false,
+	}, nil
+}
+
+// ListenAndServe listens for UDP packets on the address and responds to any query requests.
+func (s *Server) ListenAndServe(address string) error {
+	conn, err := net.ListenPacket("udp", address)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(conn)
+}
+
+// Serve responds to query requests received on the connection until the server is closed. Serve always
+// closes the connection, and returns ErrServerClosed if the server was closed using Close().
+func (s *Server) Serve(conn net.PacketConn) error {
+	s.mutex.Lock()
+
+	if s.closed {
+		s.mutex.Unlock()
+
+		conn.Close()
+
+		return ErrServerClosed
+	}
+
+	s.conn = conn
+
+	s.mutex.Unlock()
+
+	defer conn.Close()
+
+	data := make([]byte, udp.MaxDatagramSize)
+
+	for {
+		n, addr, err := conn.ReadFrom(data)
+
+		if err != nil {
+			s.mutex.Lock()
+			closed := s.closed
+			s.mutex.Unlock()
+
+			if closed {
+				return ErrServerClosed
+			}
+
+			return err
+		}
+
+		reply, err := s.handlePacket(data[:n], addr, time.Now())
+
+		// Invalid requests are ignored the same way as the vanilla server does.
+		if err != nil || reply == nil {
+			continue
+		}
+
+		// A failure to send the reply only affects this client, so the server keeps running.
+		conn.WriteTo(reply, addr)
+	}
+}
+
+// Close stops the server and closes the connection it is serving on.
+func (s *Server) Close() error {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.closed = true
+
+	if s.conn == nil {
+		return nil
+	}
+
+	return s.conn.Close()
+}
+
+func (s *Server) handlePacket(data []byte, addr net.Addr, now time.Time) ([]byte, error) {
+	r := bytes.NewReader(data)
+
+	// Magic - uint16
+	{
+		value := make([]byte, 2)
+
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(value, magic) {
+			return nil, nil
+		}
+	}
+
+	var (
+		packetType byte
+		sessionID  int32
+	)
+
+	// Type - byte
+	if err := binary.Read(r, binary.BigEndian, &packetType); err != nil {
+		return nil, err
+	}
+
+	// Session ID - int32
+	if err := binary.Read(r, binary.BigEndian, &sessionID); err != nil {
+		return nil, err
+	}
+
+	switch packetType {
+	case 0x09:
+		{
+			// Handshake request packet
+			// https://wiki.vg/Query#Request
+			return writeHandshakeResponse(sessionID, s.challengeToken(addr, now))
+		}
+	case 0x00:
+		{
+			var challengeToken int32
+
+			// Challenge Token - int32
+			if err := binary.Read(r, binary.BigEndian, &challengeToken); err != nil {
+				return nil, err
+			}
+
+			if !s.validChallengeToken(addr, challengeToken, now) {
+				return nil, nil
+			}
+
+			// The full stat request is the same as the basic stat request, but with 4 bytes of padding.
+			if r.Len() >= 4 {
+				result, err := s.provider.QueryFull()
+
+				if err != nil || result == nil {
+					return nil, err
+				}
+
+				// Full stat response packet
+				// https://wiki.vg/Query#Response_3
+				return writeFullStatResponse(sessionID, result)
+			}
+
+			result, err := s.provider.QueryBasic()
+
+			if err != nil || result == nil {
+				return nil, err
+			}
+
+			// Basic stat response packet
+			// https://wiki.vg/Query#Response_2
+			return writeBasicStatResponse(sessionID, result)
+		}
+	default:
+		return nil, nil
+	}
+}
+
+// challengeToken returns the challenge token of the address for the rotation period of the time. Tokens
+// are derived from a random secret, so they do not need to be stored and cannot be guessed.
+func (s *Server) challengeToken(addr net.Addr, now time.Time) int32 {
+	hash := hmac.New(sha256.New, s.secret)
+
+	binary.Write(hash, binary.BigEndian, now.UnixNano()/int64(ChallengeTokenLifetime))
+	hash.Write([]byte(addr.String()))
+
+	return int32(binary.BigEndian.Uint32(hash.Sum(nil)) & 0x7FFFFFFF)
+}
+
+func (s *Server) validChallengeToken(addr net.Addr, challengeToken int32, now time.Time) bool {
+	return challengeToken == s.challengeToken(addr, now) || challengeToken == s.challengeToken(addr, now.Add(-ChallengeTokenLifetime))
+}
+
+func writeHandshakeResponse(sessionID int32, challengeToken int32) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	// Type - byte
+	if err := buf.WriteByte(0x09); err != nil {
+		return nil, err
+	}
+
+	// Session ID - int32
+	if err := binary.Write(buf, binary.BigEndian, sessionID); err != nil {
+		return nil, err
+	}
+
+	// Challenge Token - null-terminated string
+	if err := writeNTString(buf, strconv.FormatInt(int64(challengeToken), 10)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeBasicStatResponse(sessionID int32, data *response.QueryBasic) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	// Type - byte
+	if err := buf.WriteByte(0x00); err != nil {
+		return nil, err
+	}
+
+	// Session ID - int32
+	if err := binary.Write(buf, binary.BigEndian, sessionID); err != nil {
+		return nil, err
+	}
+
+	// MOTD, Game Type, Map, Online Players, Max Players - null-terminated strings
+	for _, value := range []string{
+		data.MOTD.Raw,
+		data.GameType,
+		data.Map,
+		strconv.FormatUint(data.OnlinePlayers, 10),
+		strconv.FormatUint(data.MaxPlayers, 10),
+	} {
+		if err := writeNTString(buf, value); err != nil {
+			return nil, err
+		}
+	}
+
+	// Host Port - uint16
+	if err := binary.Write(buf, binary.LittleEndian, data.HostPort); err != nil {
+		return nil, err
+	}
+
+	// Host IP - null-terminated string
+	if err := writeNTString(buf, data.HostIP); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeFullStatResponse(sessionID int32, data *response.QueryFull) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	// Type - byte
+	if err := buf.WriteByte(0x00); err != nil {
+		return nil, err
+	}
+
+	// Session ID - int32
+	if err := binary.Write(buf, binary.BigEndian, sessionID); err != nil {
+		return nil, err
+	}
+
+	// Padding - [11]byte
+	if _, err := buf.Write(fullStatPadding); err != nil {
+		return nil, err
+	}
+
+	// K, V section - null-terminated key,pair pair string
+	{
+		values := fullStatKeyValues(data)
+
+		// The keys known by the vanilla server are written in the same order as the vanilla
+		// server, followed by any other keys in alphabetical order.
+		keys := make([]string, 0, len(values))
+
+		for _, key := range fullStatKeyOrder {
+			if _, ok := values[key]; ok {
+				keys = append(keys, key)
+			}
+		}
+
+		extraKeys := make([]string, 0)
+
+		for key := range values {
+			if !contains(fullStatKeyOrder, key) && len(key) > 0 {
+				extraKeys = append(extraKeys, key)
+			}
+		}
+
+		sort.Strings(extraKeys)
+
+		for _, key := range append(keys, extraKeys...) {
+			if err := writeNTString(buf, key); err != nil {
+				return nil, err
+			}
+
+			if err := writeNTString(buf, values[key]); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := buf.WriteByte(0x00); err != nil {
+			return nil, err
+		}
+	}
+
+	// Padding - [10]byte
+	if _, err := buf.Write(playersPadding); err != nil {
+		return nil, err
+	}
+
+	// Players section - null-terminated strings
+	{
+		for _, username := range data.Players {
+			if len(username) < 1 {
+				continue
+			}
+
+			if err := writeNTString(buf, username); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := buf.WriteByte(0x00); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fullStatKeyValues returns the key/value data of a full stat response. The typed fields and extra keys
+// are used as the base, and any keys in the raw data take precedence over them.
+func fullStatKeyValues(data *response.QueryFull) map[string]string {
+	result := make(map[string]string)
+
+	for key, value := range data.Extra {
+		result[key] = value
+	}
+
+	for key, value := range map[string]string{
+		"hostname": data.MOTD.Raw,
+		"gametype": data.GameType,
+		"game_id":  data.GameID,
+		"version":  data.Version,
+		"plugins":  FormatPlugins(data.Plugins),
+		"map":      data.Map,
+		"hostip":   data.HostIP,
+	} {
+		if len(value) > 0 {
+			result[key] = value
+		}
+	}
+
+	result["numplayers"] = strconv.FormatUint(data.OnlinePlayers, 10)
+	result["maxplayers"] = strconv.FormatUint(data.MaxPlayers, 10)
+
+	if data.HostPort > 0 {
+		result["hostport"] = strconv.FormatUint(uint64(data.HostPort), 10)
+	}
+
+	if len(data.ServerEngine) > 0 {
+		result["server_engine"] = data.ServerEngine
+	}
+
+	if data.Whitelist != nil {
+		result["whitelist"] = "off"
+
+		if *data.Whitelist {
+			result["whitelist"] = "on"
+		}
+	}
+
+	for key, value := range data.Data {
+		result[key] = value
+	}
+
+	return result
+}
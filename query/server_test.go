@@ -0,0 +1,234 @@
+package query_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/query"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+type testProvider struct{}
+
+func (testProvider) QueryBasic() (*response.QueryBasic, error) {
+	motd, err := formatting.Parse("§aA Minecraft Server")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.QueryBasic{
+		MOTD:          *motd,
+		GameType:      "SMP",
+		Map:           "world",
+		OnlinePlayers: 2,
+		MaxPlayers:    20,
+		HostPort:      25565,
+		HostIP:        "127.0.0.1",
+	}, nil
+}
+
+func (testProvider) QueryFull() (*response.QueryFull, error) {
+	return &response.QueryFull{
+		Data: map[string]string{
+			"hostname":   "§aA Minecraft Server",
+			"gametype":   "SMP",
+			"game_id":    "MINECRAFT",
+			"version":    "1.20.4",
+			"plugins":    "",
+			"map":        "world",
+			"numplayers": "2",
+			"maxplayers": "20",
+			"hostport":   "25565",
+			"hostip":     "127.0.0.1",
+		},
+		Players: []string{"PassTheMayo", "Notch"},
+	}, nil
+}
+
+// nonASCIIProvider responds with text that is also valid UTF-8 when encoded as ISO-8859-1.
+type nonASCIIProvider struct{}
+
+func (nonASCIIProvider) QueryBasic() (*response.QueryBasic, error) {
+	motd, err := formatting.Parse("§aCafé Ã© ☃")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.QueryBasic{
+		MOTD:          *motd,
+		GameType:      "SMP",
+		Map:           "wörld",
+		OnlinePlayers: 2,
+		MaxPlayers:    20,
+		HostPort:      25565,
+		HostIP:        "127.0.0.1",
+	}, nil
+}
+
+func (nonASCIIProvider) QueryFull() (*response.QueryFull, error) {
+	return &response.QueryFull{
+		Data: map[string]string{
+			"hostname":   "§aCafé Ã© ☃",
+			"gametype":   "SMP",
+			"game_id":    "MINECRAFT",
+			"version":    "1.20.4",
+			"plugins":    "",
+			"map":        "wörld",
+			"numplayers": "2",
+			"maxplayers": "20",
+			"hostport":   "25565",
+			"hostip":     "127.0.0.1",
+		},
+		Players: []string{"Jürgen", "Ã©lodie"},
+	}, nil
+}
+
+type nilProvider struct{}
+
+func (nilProvider) QueryBasic() (*response.QueryBasic, error) {
+	return nil, nil
+}
+
+func (nilProvider) QueryFull() (*response.QueryFull, error) {
+	return nil, nil
+}
+
+func startTestServer(t *testing.T) (*query.Server, uint16) {
+	return startProviderServer(t, testProvider{})
+}
+
+func startProviderServer(t *testing.T, provider query.Provider) (*query.Server, uint16) {
+	server, err := query.NewServer(provider)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go server.Serve(conn)
+
+	t.Cleanup(func() {
+		server.Close()
+	})
+
+	return server, uint16(conn.LocalAddr().(*net.UDPAddr).Port)
+}
+
+func TestServerBasic(t *testing.T) {
+	_, port := startTestServer(t)
+
+	resp, err := query.Basic(context.Background(), "127.0.0.1", port)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := testProvider{}.QueryBasic()
+
+	if resp.MOTD.Raw != expected.MOTD.Raw || resp.GameType != expected.GameType || resp.Map != expected.Map ||
+		resp.OnlinePlayers != expected.OnlinePlayers || resp.MaxPlayers != expected.MaxPlayers ||
+		resp.HostPort != expected.HostPort || resp.HostIP != expected.HostIP {
+		t.Fatalf("unexpected basic response: %+v", resp)
+	}
+}
+
+func TestServerFull(t *testing.T) {
+	_, port := startTestServer(t)
+
+	resp, err := query.Full(context.Background(), "127.0.0.1", port)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := testProvider{}.QueryFull()
+
+	if !reflect.DeepEqual(resp.Data, expected.Data) || !reflect.DeepEqual(resp.Players, expected.Players) {
+		t.Fatalf("unexpected full response: %+v", resp)
+	}
+
+	if resp.MOTD.Clean != "A Minecraft Server" || resp.GameID != "MINECRAFT" || resp.Version != "1.20.4" ||
+		resp.OnlinePlayers != 2 || resp.MaxPlayers != 20 || resp.HostPort != 25565 || len(resp.Extra) != 0 {
+		t.Fatalf("unexpected typed full response: %+v", resp)
+	}
+}
+
+func TestServerInvalidChallengeToken(t *testing.T) {
+	_, port := startTestServer(t)
+
+	conn, err := net.Dial("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	buf := &bytes.Buffer{}
+	buf.Write([]byte{0xFE, 0xFD, 0x00})
+	binary.Write(buf, binary.BigEndian, int32(1))
+	binary.Write(buf, binary.BigEndian, int32(12345))
+
+	if _, err = conn.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Millisecond * 200))
+
+	if _, err = conn.Read(make([]byte, 1500)); err == nil {
+		t.Fatal("server responded to a stat request with an invalid challenge token")
+	}
+}
+
+func TestServerNilResult(t *testing.T) {
+	_, port := startProviderServer(t, nilProvider{})
+
+	if _, err := query.Basic(context.Background(), "127.0.0.1", port, options.Query{Timeout: time.Millisecond * 300}); err == nil {
+		t.Fatal("expected the basic stat request to be left unanswered")
+	}
+
+	if _, err := query.Full(context.Background(), "127.0.0.1", port, options.Query{Timeout: time.Millisecond * 300}); err == nil {
+		t.Fatal("expected the full stat request to be left unanswered")
+	}
+}
+
+func TestServerNonASCII(t *testing.T) {
+	_, port := startProviderServer(t, nonASCIIProvider{})
+
+	basic, err := query.Basic(context.Background(), "127.0.0.1", port)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if basic.MOTD.Raw != "§aCafé Ã© ☃" || basic.Map != "wörld" {
+		t.Fatalf("unexpected basic response: motd=%q, map=%q", basic.MOTD.Raw, basic.Map)
+	}
+
+	full, err := query.Full(context.Background(), "127.0.0.1", port)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := nonASCIIProvider{}.QueryFull()
+
+	if !reflect.DeepEqual(full.Data, expected.Data) || !reflect.DeepEqual(full.Players, expected.Players) {
+		t.Fatalf("unexpected full response: data=%q, players=%q", full.Data, full.Players)
+	}
+}
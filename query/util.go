@@ -2,51 +2,81 @@ package query
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
-	"fmt"
 	"io"
+	"net"
 	"strconv"
 	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/internal/udp"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/util"
 )
 
 var (
 	defaultQueryOptions = options.Query{
 		Timeout:   time.Second * 5,
 		SessionID: 0,
+		Retry: options.Retry{
+			Attempts: 3,
+		},
 	}
 	magic = []byte{0xFE, 0xFD}
 )
 
-func convertISO8859ToUTF8(data []byte) string {
-	result := make([]rune, len(data))
-
-	for i, b := range data {
-		result[i] = rune(b)
-	}
+func performHandshake(ctx context.Context, conn *udp.Conn, deadline time.Time, opts options.Query) (int32, error) {
+	var challengeToken int32
 
-	return string(result)
+	err := udp.Exchange(
+		ctx,
+		conn,
+		deadline,
+		opts.Retry,
+		func(attempt int) ([]byte, error) {
+			buf := &bytes.Buffer{}
+
+			// Handshake request packet
+			// https://wiki.vg/Query#Request
+			if err := writeHandshakeRequest(buf, opts.SessionID); err != nil {
+				return nil, err
+			}
+
+			return buf.Bytes(), nil
+		},
+		func(data []byte, addr net.Addr) (bool, error) {
+			if !isResponse(data, 0x09, opts.SessionID) {
+				return false, nil
+			}
+
+			// Handshake response packet
+			// https://wiki.vg/Query#Response
+			value, err := readHandshakeResponse(data, opts.SessionID)
+
+			if err != nil {
+				return false, err
+			}
+
+			challengeToken = value
+
+			return true, nil
+		},
+	)
+
+	return challengeToken, err
 }
 
-func readNTString(r io.Reader) (string, error) {
-	data := make([]byte, 0)
-
-	for {
-		chunk := make([]byte, 1)
-
-		if _, err := r.Read(chunk); err != nil {
-			return "", err
-		}
-
-		if chunk[0] == 0x00 {
-			break
-		}
-
-		data = append(data, chunk...)
-	}
+// isResponse reports whether the datagram is a response of the packet type for the session. Any other
+// datagram is a duplicate or late response to an earlier request, or was not sent by the server when
+// using an unconnected socket.
+func isResponse(data []byte, packetType byte, sessionID int32) bool {
+	return len(data) >= 5 && data[0] == packetType && int32(binary.BigEndian.Uint32(data[1:5])) == sessionID
+}
 
-	return convertISO8859ToUTF8(data), nil
+// isFullStatResponse reports whether the stat response datagram starts with the padding of a full stat response.
+func isFullStatResponse(data []byte) bool {
+	return len(data) >= 5+len(fullStatPadding) && bytes.Equal(data[5:5+len(fullStatPadding)], fullStatPadding)
 }
 
 func writeHandshakeRequest(w io.Writer, sessionID int32) error {
@@ -74,30 +104,32 @@ func writeHandshakeRequest(w io.Writer, sessionID int32) error {
 	return nil
 }
 
-func readHandshakeResponse(r io.Reader, sessionID int32) (int32, error) {
+func readHandshakeResponse(data []byte, sessionID int32) (int32, error) {
+	r := newDatagramReader(data)
+
 	// Type - byte
 	{
-		var packetType byte
+		packetType, err := r.readByte("packet type")
 
-		if err := binary.Read(r, binary.BigEndian, &packetType); err != nil {
+		if err != nil {
 			return 0, err
 		}
 
 		if packetType != 0x09 {
-			return 0, fmt.Errorf("query: received unexpected packet type (expected=0x00, received=0x%02X)", packetType)
+			return 0, mcerrors.NewUnexpectedPacket("query", 0x09, int64(packetType))
 		}
 	}
 
 	// Session ID - int32
 	{
-		var serverSessionID int32
+		serverSessionID, err := r.readInt32("session ID")
 
-		if err := binary.Read(r, binary.BigEndian, &serverSessionID); err != nil {
+		if err != nil {
 			return 0, err
 		}
 
 		if serverSessionID != sessionID {
-			return 0, fmt.Errorf("query: session ID mismatch (expected=%d, received=%d)", sessionID, serverSessionID)
+			return 0, mcerrors.NewPayloadMismatch("query", "session ID", sessionID, serverSessionID)
 		}
 	}
 
@@ -105,7 +137,7 @@ func readHandshakeResponse(r io.Reader, sessionID int32) (int32, error) {
 
 	// Challenge Token - null-terminated string
 	{
-		challengeTokenString, err := readNTString(r)
+		challengeTokenString, err := r.readNTString("challenge token")
 
 		if err != nil {
 			return 0, err
@@ -114,7 +146,7 @@ func readHandshakeResponse(r io.Reader, sessionID int32) (int32, error) {
 		value, err := strconv.ParseInt(challengeTokenString, 10, 32)
 
 		if err != nil {
-			return 0, err
+			return 0, mcerrors.NewProtocolError(nil, challengeTokenString, "query: invalid challenge token: %q", challengeTokenString)
 		}
 
 		challengeToken = int32(value)
@@ -122,3 +154,38 @@ func readHandshakeResponse(r io.Reader, sessionID int32) (int32, error) {
 
 	return challengeToken, nil
 }
+
+// writeNTString writes a null-terminated string encoded as UTF-8, which is the encoding that the client tries
+// first. Any null bytes in the string are removed since they would terminate the string early.
+func writeNTString(w io.Writer, value string) error {
+	_, err := w.Write(append(bytes.ReplaceAll([]byte(value), []byte{0x00}, nil), 0x00))
+
+	return err
+}
+
+// queryPort returns the port to query, which is the default port of the edition if the port is 0.
+func queryPort(port uint16, opts options.Query) uint16 {
+	if port != 0 {
+		return port
+	}
+
+	if opts.Bedrock {
+		return util.DefaultBedrockPort
+	}
+
+	return util.DefaultJavaPort
+}
+
+func pointerOf[T any](v T) *T {
+	return &v
+}
+
+func contains[T comparable](arr []T, v T) bool {
+	for _, a := range arr {
+		if a == v {
+			return true
+		}
+	}
+
+	return false
+}
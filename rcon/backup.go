@@ -0,0 +1,202 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+)
+
+var (
+	// ErrSaveNotConfirmed means the response to the save command did not confirm that the world was saved.
+	ErrSaveNotConfirmed = errors.New("rcon: server did not confirm the world was saved")
+)
+
+var (
+	defaultBackupOptions = options.RCONBackup{
+		StepTimeout:      time.Second * 10,
+		SaveTimeout:      time.Minute * 5,
+		BackupTimeout:    0,
+		SaveConfirmation: "Saved the game",
+	}
+)
+
+// BackupStepName is the name of a step of a backup.
+type BackupStepName string
+
+var (
+	// BackupStepSaveOff disables automatic saving so the world files do not change during the backup.
+	BackupStepSaveOff BackupStepName = "save-off"
+	// BackupStepSave writes all pending changes to the world files.
+	BackupStepSave BackupStepName = "save"
+	// BackupStepBackup calls the backup function.
+	BackupStepBackup BackupStepName = "backup"
+	// BackupStepSaveOn enables automatic saving again.
+	BackupStepSaveOn BackupStepName = "save-on"
+)
+
+// BackupReport is what happened during a backup, with the steps in the order they were run.
+type BackupReport struct {
+	Steps []BackupStep
+}
+
+// BackupStep is the result of a single step of a backup.
+type BackupStep struct {
+	Name BackupStepName
+	// Command is the command executed by the step, or an empty string for the backup function.
+	Command  string
+	Output   string
+	Started  time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Err returns the errors of all steps joined together, or nil if every step succeeded.
+func (r BackupReport) Err() error {
+	errs := make([]error, 0)
+
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, step.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Backup makes a consistent backup of the world. It disables automatic saving, saves the world, waits for
+// the server to confirm the save finished, and calls the backup function to copy the world files. Automatic
+// saving is always enabled again afterwards, even if a step fails, the context is cancelled or the backup
+// function panics. The report is returned along with the errors of any steps that failed.
+func Backup(ctx context.Context, executor Executor, backup func(ctx context.Context) error, options ...options.RCONBackup) (report *BackupReport, err error) {
+	opts := parseBackupOptions(options...)
+
+	report = &BackupReport{
+		Steps: make([]BackupStep, 0, 4),
+	}
+
+	// Automatic saving is enabled again without the deadline or cancellation of the context, since leaving
+	// it disabled means the changes made to the world are lost when the server stops.
+	defer func() {
+		step := runBackupCommand(context.WithoutCancel(ctx), executor, BackupStepSaveOn, "save-on", opts.StepTimeout)
+
+		report.Steps = append(report.Steps, step)
+
+		if step.Err != nil {
+			err = errors.Join(err, fmt.Errorf("%s: %w", step.Name, step.Err))
+		}
+	}()
+
+	return report, backupSteps(ctx, executor, backup, opts, report)
+}
+
+// backupSteps runs the steps of the backup before automatic saving is enabled again, stopping at the first
+// step that fails.
+func backupSteps(ctx context.Context, executor Executor, backup func(ctx context.Context) error, opts options.RCONBackup, report *BackupReport) error {
+	step := runBackupCommand(ctx, executor, BackupStepSaveOff, "save-off", opts.StepTimeout)
+
+	report.Steps = append(report.Steps, step)
+
+	if step.Err != nil {
+		return fmt.Errorf("%s: %w", step.Name, step.Err)
+	}
+
+	step = runBackupCommand(ctx, executor, BackupStepSave, "save-all flush", opts.SaveTimeout)
+
+	if step.Err == nil && !strings.Contains(step.Output, opts.SaveConfirmation) {
+		step.Err = ErrSaveNotConfirmed
+	}
+
+	report.Steps = append(report.Steps, step)
+
+	if step.Err != nil {
+		return fmt.Errorf("%s: %w", step.Name, step.Err)
+	}
+
+	backupCtx, cancel := ctx, context.CancelFunc(func() {})
+
+	if opts.BackupTimeout > 0 {
+		backupCtx, cancel = context.WithTimeout(ctx, opts.BackupTimeout)
+	}
+
+	defer cancel()
+
+	step = BackupStep{
+		Name:     BackupStepBackup,
+		Command:  "",
+		Output:   "",
+		Started:  time.Now(),
+		Duration: 0,
+		Err:      nil,
+	}
+
+	// The step is added to the report before calling the function, so it is reported even if it panics.
+	report.Steps = append(report.Steps, step)
+
+	err := backup(backupCtx)
+
+	if err == nil {
+		err = backupCtx.Err()
+	}
+
+	report.Steps[len(report.Steps)-1].Duration = time.Since(step.Started)
+	report.Steps[len(report.Steps)-1].Err = err
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", step.Name, err)
+	}
+
+	return nil
+}
+
+// runBackupCommand executes the command of a backup step with the timeout.
+func runBackupCommand(ctx context.Context, executor Executor, name BackupStepName, command string, timeout time.Duration) BackupStep {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	defer cancel()
+
+	result := BackupStep{
+		Name:     name,
+		Command:  command,
+		Output:   "",
+		Started:  time.Now(),
+		Duration: 0,
+		Err:      nil,
+	}
+
+	resp, err := executor.ExecuteResponse(ctx, command)
+
+	result.Duration = time.Since(result.Started)
+	result.Err = err
+
+	if resp != nil {
+		result.Output = resp.Output
+	}
+
+	return result
+}
+
+func parseBackupOptions(opts ...options.RCONBackup) options.RCONBackup {
+	if len(opts) < 1 {
+		return defaultBackupOptions
+	}
+
+	result := opts[0]
+
+	if result.StepTimeout <= 0 {
+		result.StepTimeout = defaultBackupOptions.StepTimeout
+	}
+
+	if result.SaveTimeout <= 0 {
+		result.SaveTimeout = defaultBackupOptions.SaveTimeout
+	}
+
+	if len(result.SaveConfirmation) < 1 {
+		result.SaveConfirmation = defaultBackupOptions.SaveConfirmation
+	}
+
+	return result
+}
@@ -0,0 +1,153 @@
+package rcon_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+func TestBackup(t *testing.T) {
+	server := newBackupServer("Saving the game (this may take a moment!)Saved the game")
+	client := dialTestServer(t, server.handle)
+
+	report, err := rcon.Backup(context.Background(), client, func(ctx context.Context) error {
+		if commands := server.Commands(); !slices.Equal(commands, []string{"save-off", "save-all flush"}) {
+			t.Errorf("unexpected commands before the backup: %v", commands)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if commands := server.Commands(); !slices.Equal(commands, []string{"save-off", "save-all flush", "save-on"}) {
+		t.Fatalf("unexpected commands: %v", commands)
+	}
+
+	names := make([]rcon.BackupStepName, 0)
+
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+	}
+
+	if !slices.Equal(names, []rcon.BackupStepName{rcon.BackupStepSaveOff, rcon.BackupStepSave, rcon.BackupStepBackup, rcon.BackupStepSaveOn}) {
+		t.Fatalf("unexpected steps: %v", names)
+	}
+
+	if report.Steps[0].Output != "Automatic saving is now disabled" {
+		t.Fatalf("unexpected output: %q", report.Steps[0].Output)
+	}
+}
+
+func TestBackupFailure(t *testing.T) {
+	errBackup := errors.New("disk full")
+
+	server := newBackupServer("Saved the game")
+	client := dialTestServer(t, server.handle)
+
+	report, err := rcon.Backup(context.Background(), client, func(ctx context.Context) error {
+		return errBackup
+	})
+
+	if !errors.Is(err, errBackup) || !errors.Is(report.Err(), errBackup) {
+		t.Fatalf("expected the backup error, received %v", err)
+	}
+
+	if commands := server.Commands(); commands[len(commands)-1] != "save-on" {
+		t.Fatalf("expected saving to be enabled again: %v", commands)
+	}
+}
+
+func TestBackupCancelled(t *testing.T) {
+	server := newBackupServer("Saved the game")
+	client := dialTestServer(t, server.handle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := rcon.Backup(ctx, client, func(ctx context.Context) error {
+		cancel()
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, received %v", err)
+	}
+
+	if commands := server.Commands(); commands[len(commands)-1] != "save-on" {
+		t.Fatalf("expected saving to be enabled again: %v", commands)
+	}
+}
+
+func TestBackupNotConfirmed(t *testing.T) {
+	server := newBackupServer("Saving the game (this may take a moment!)")
+	client := dialTestServer(t, server.handle)
+
+	called := false
+
+	_, err := rcon.Backup(context.Background(), client, func(ctx context.Context) error {
+		called = true
+
+		return nil
+	})
+
+	if !errors.Is(err, rcon.ErrSaveNotConfirmed) {
+		t.Fatalf("expected ErrSaveNotConfirmed, received %v", err)
+	}
+
+	if called {
+		t.Fatal("expected the backup function not to be called")
+	}
+
+	if commands := server.Commands(); !slices.Equal(commands, []string{"save-off", "save-all flush", "save-on"}) {
+		t.Fatalf("unexpected commands: %v", commands)
+	}
+}
+
+// backupServer records the commands it receives and responds like the vanilla server, with the response
+// to the save command given by the test.
+type backupServer struct {
+	mu       sync.Mutex
+	commands []string
+	save     string
+}
+
+func newBackupServer(save string) *backupServer {
+	return &backupServer{
+		commands: make([]string, 0),
+		save:     save,
+	}
+}
+
+func (s *backupServer) handle(command string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = append(s.commands, command)
+
+	switch command {
+	case "save-off":
+		return "Automatic saving is now disabled"
+	case "save-on":
+		return "Automatic saving is now enabled"
+	case "save-all flush":
+		return s.save
+	default:
+		return "Unknown command"
+	}
+}
+
+func (s *backupServer) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.commands...)
+}
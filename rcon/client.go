@@ -3,14 +3,18 @@ package rcon
 import (
 	"bytes"
 	"context"
-	"encoding/binary"
 	"errors"
 	"fmt"
-	"io"
 	"net"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/internal/charset"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/response"
 )
 
 var (
@@ -19,9 +23,12 @@ var (
 	// ErrAlreadyLoggedIn means the RCON client was already logged in but a second login attempt was made.
 	ErrAlreadyLoggedIn = errors.New("rcon: already successfully logged in")
 	// ErrInvalidPassword means the password used in the RCON login was incorrect.
-	ErrInvalidPassword = errors.New("rcon: incorrect password")
+	ErrInvalidPassword error = mcerrors.New(mcerrors.ErrAuth, "rcon: incorrect password")
 	// ErrNotAuthenticated means the client attempted to execute a command before a login was successful.
 	ErrNotAuthenticated = errors.New("rcon: not authenticated with the server")
+	// ErrConnectionLost means the connection was closed after the command was sent but before its response
+	// was received, so the command may or may not have been executed by the server.
+	ErrConnectionLost = errors.New("rcon: connection lost while waiting for a response")
 )
 
 var (
@@ -30,315 +37,620 @@ var (
 	}
 )
 
-// Client is a client for interacting with RCON and contains multiple methods.
+// Client is a client for interacting with RCON and contains multiple methods. It is safe to use from
+// multiple goroutines at once.
 type Client struct {
-	conn        net.Conn
+	// Messages receives the responses to commands sent with Run. Responses to commands sent with Execute
+	// are returned by Execute instead. Responses are queued until they are read, so a channel that is not
+	// read from never blocks the client, but the queue grows with every response to Run.
 	Messages    chan string
-	runTrigger  chan bool
+	conn        net.Conn
+	done        chan struct{}
+	mu          sync.Mutex
+	writeMu     sync.Mutex
+	opts        options.RCON
 	authSuccess bool
 	requestID   int32
+	pending     map[int32]*pendingRequest
+	abandoned   map[int32]*pendingRequest
+	queued      []string
+	notify      chan struct{}
+	readErr     error
+}
+
+// pendingRequest is a command sent with Execute that is waiting for its response. Commands with
+// responses longer than 4096 bytes are split across multiple packets by the server, so the command is
+// followed by a sentinel request and all packets are collected until the response to the sentinel.
+type pendingRequest struct {
+	requestID  int32
+	sentinelID int32
+	fragments  []string
+	result     chan *response.RCON
 }
 
 // Dial connects to the server using the address provided and returns a new client.
 func Dial(hostname string, port uint16, options ...options.RCON) (*Client, error) {
+	return DialContext(context.Background(), hostname, port, options...)
+}
+
+// DialContext connects to the server using the address provided and returns a new client, giving up when
+// either the context is done or the timeout in the options is reached.
+func DialContext(ctx context.Context, hostname string, port uint16, options ...options.RCON) (*Client, error) {
 	opts := parseOptions(options...)
 
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", hostname, port), opts.Timeout)
+	dialer := net.Dialer{
+		Timeout: opts.Timeout,
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", hostname, port))
 
 	if err != nil {
-		return nil, err
+		return nil, mcerrors.Classify(err)
 	}
 
 	return &Client{
-		conn:        conn,
 		Messages:    make(chan string),
-		runTrigger:  make(chan bool),
+		conn:        conn,
+		done:        make(chan struct{}),
+		opts:        opts,
 		authSuccess: false,
 		requestID:   0,
+		pending:     make(map[int32]*pendingRequest),
+		abandoned:   make(map[int32]*pendingRequest),
+		queued:      make([]string, 0),
+		notify:      make(chan struct{}, 1),
 	}, nil
 }
 
-// Login communicates authentication with the server using the plaintext password.
-func (r *Client) Login(password string) error {
-	if r.conn == nil {
-		return ErrNotConnected
+// dialAndLogin connects to the server and logs in using the password, using the timeout in the options
+// for each of the steps.
+func dialAndLogin(ctx context.Context, hostname string, port uint16, password string, opts options.RCON) (*Client, error) {
+	client, err := DialContext(ctx, hostname, port, opts)
+
+	if err != nil {
+		return nil, err
 	}
 
-	if r.authSuccess {
-		return ErrAlreadyLoggedIn
+	loginCtx := ctx
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		loginCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+
+		defer cancel()
 	}
 
-	requestID := r.nextRequestID()
+	if err := client.LoginContext(loginCtx, password); err != nil {
+		client.Close()
 
-	// Login request packet
-	// https://wiki.vg/RCON#3:_Login
-	{
-		buf := &bytes.Buffer{}
+		return nil, err
+	}
 
-		// Length - int32
-		if err := binary.Write(buf, binary.LittleEndian, int32(10+len(password))); err != nil {
-			return err
-		}
+	return client, nil
+}
 
-		// Request ID - int32
-		if err := binary.Write(buf, binary.LittleEndian, requestID); err != nil {
-			return err
-		}
+// Login communicates authentication with the server using the plaintext password, giving up after the
+// timeout in the options.
+func (r *Client) Login(password string) error {
+	ctx := context.Background()
 
-		// Type - int32
-		if err := binary.Write(buf, binary.LittleEndian, int32(3)); err != nil {
-			return err
-		}
+	if r.opts.Timeout > 0 {
+		var cancel context.CancelFunc
 
-		// Payload - null-terminated string
-		if _, err := buf.Write(append([]byte(password), 0x00)); err != nil {
-			return err
-		}
+		ctx, cancel = context.WithTimeout(ctx, r.opts.Timeout)
 
-		// Padding - byte
-		if err := buf.WriteByte(0x00); err != nil {
-			return err
-		}
+		defer cancel()
+	}
 
-		if _, err := io.Copy(r.conn, buf); err != nil {
-			return err
-		}
+	return r.LoginContext(ctx, password)
+}
+
+// LoginContext communicates authentication with the server using the plaintext password, giving up when
+// the context is done.
+func (r *Client) LoginContext(ctx context.Context, password string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		return ErrNotConnected
 	}
 
-	// Login response packet
-	// https://wiki.vg/RCON#3:_Login
-	{
-		var packetLength int32
+	if r.authSuccess {
+		return ErrAlreadyLoggedIn
+	}
 
-		// Length - int32
-		{
-			if err := binary.Read(r.conn, binary.LittleEndian, &packetLength); err != nil {
-				return err
-			}
-		}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		// Request ID - int32
-		{
-			var reqID int32
+	conn := r.conn
 
-			if err := binary.Read(r.conn, binary.LittleEndian, &reqID); err != nil {
-				return err
-			}
+	// The deadline interrupts the blocking reads and writes once the context is done, and is removed
+	// again before the connection is handed to the background reader.
+	fired := make(chan struct{})
 
-			if reqID == -1 {
-				return ErrInvalidPassword
-			} else if reqID != requestID {
-				return fmt.Errorf("rcon: received unexpected request ID (expected=%d, received=%d)", requestID, reqID)
-			}
-		}
+	stop := context.AfterFunc(ctx, func() {
+		defer close(fired)
 
-		// Type - int32
-		{
-			var packetType int32
+		conn.SetDeadline(time.Now())
+	})
 
-			if err := binary.Read(r.conn, binary.LittleEndian, &packetType); err != nil {
-				return err
-			}
+	err := r.login(conn, password)
 
-			if packetType != 0x02 {
-				return fmt.Errorf("rcon: received unexpected packet type (expected=0x02, received=0x%02X)", packetType)
-			}
-		}
+	if !stop() {
+		<-fired
+	}
 
-		// Remaining bytes
-		{
-			data := make([]byte, packetLength-8)
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return ctxErr
+	}
 
-			if _, err := r.conn.Read(data); err != nil {
-				return err
-			}
-		}
+	if err != nil {
+		return err
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return err
 	}
 
 	r.authSuccess = true
 
-	go (func() {
-		for {
-			err := r.readMessage()
+	go r.readLoop(conn)
+	go r.deliverLoop(r.done)
 
-			if err != nil {
-				fmt.Println(err)
+	return nil
+}
 
-				if r.conn != nil {
-					r.conn.Close()
-					r.conn = nil
-				}
+// login sends the login packet and reads the response. The caller must hold the lock.
+func (r *Client) login(conn net.Conn, password string) error {
+	requestID := r.nextRequestID()
 
-				break
-			}
+	// Login request packet
+	// https://wiki.vg/RCON#3:_Login
+	if err := r.writePacket(conn, requestID, PacketTypeLogin, password); err != nil {
+		return err
+	}
+
+	// Login response packet
+	// https://wiki.vg/RCON#3:_Login
+	{
+		packet, err := ReadPacket(conn)
+
+		if err != nil {
+			return mcerrors.Classify(err)
+		}
+
+		if packet.RequestID == -1 {
+			return ErrInvalidPassword
+		} else if packet.RequestID != requestID {
+			return mcerrors.NewPayloadMismatch("rcon", "request ID", requestID, packet.RequestID)
 		}
-	})()
+
+		if packet.Type != PacketTypeAuthResponse {
+			return mcerrors.NewUnexpectedPacket("rcon", int64(PacketTypeAuthResponse), int64(packet.Type))
+		}
+	}
 
 	return nil
 }
 
-// Run executes the command on the server but does not wait for a response.
+// Run executes the command on the server but does not wait for a response. The response is sent to the
+// Messages channel, which must be read from to receive the responses. Commands containing a null byte or longer than
+// MaxPayloadLength are not sent.
 func (r *Client) Run(command string) error {
+	if err := checkCommand(command); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+
 	if r.conn == nil {
+		r.mu.Unlock()
+
 		return ErrNotConnected
 	}
 
 	if !r.authSuccess {
+		r.mu.Unlock()
+
 		return ErrNotAuthenticated
 	}
 
+	conn := r.conn
 	requestID := r.nextRequestID()
 
+	r.mu.Unlock()
+
 	// Command packet
 	// https://wiki.vg/RCON#2:_Command
-	{
-		buf := &bytes.Buffer{}
+	return r.writePacket(conn, requestID, PacketTypeCommand, command)
+}
 
-		// Length - int32
-		if err := binary.Write(buf, binary.LittleEndian, int32(10+len(command))); err != nil {
-			return err
-		}
+// Execute runs the command on the server and waits for the response to that command. If the context is
+// done before the response is received, the classified context error is returned.
+func (r *Client) Execute(ctx context.Context, command string) (string, error) {
+	result, err := r.ExecuteResponse(ctx, command)
 
-		// Request ID - int32
-		if err := binary.Write(buf, binary.LittleEndian, requestID); err != nil {
-			return err
-		}
+	if err != nil {
+		return "", err
+	}
 
-		// Type - int32
-		if err := binary.Write(buf, binary.LittleEndian, int32(2)); err != nil {
-			return err
-		}
+	return result.Output, nil
+}
 
-		// Payload - null-terminated string
-		if _, err := buf.Write(append([]byte(command), 0x00)); err != nil {
-			return err
-		}
+// ExecuteFormatted runs the command on the server and waits for the response to that command, parsing
+// the formatting codes in the response.
+func (r *Client) ExecuteFormatted(ctx context.Context, command string) (*formatting.Result, error) {
+	result, err := r.ExecuteResponse(ctx, command)
 
-		if err := buf.WriteByte(0x00); err != nil {
-			return err
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		if _, err := io.Copy(r.conn, buf); err != nil {
-			return err
-		}
+	return formatting.Parse(result.Output)
+}
+
+// ExecuteResponse runs the command on the server and waits for the full response to that command,
+// reassembling responses that were split across multiple packets. Commands containing a null byte or
+// longer than MaxPayloadLength are not sent.
+func (r *Client) ExecuteResponse(ctx context.Context, command string) (*response.RCON, error) {
+	if err := checkCommand(command); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return r.send(ctx, PacketTypeCommand, command, !r.opts.DisableReassembly)
 }
 
-// Execute runs the command on the server and attempts to wait for a response.
-func (r *Client) Execute(ctx context.Context, command string) (string, error) {
+// ping checks that the connection is still usable by sending a packet with the command response type,
+// which the server responds to without executing anything.
+func (r *Client) ping(ctx context.Context) error {
+	_, err := r.send(ctx, PacketTypeResponse, "", false)
+
+	return err
+}
+
+// send sends a packet and waits for its response, following it with a sentinel packet if reassemble is
+// true.
+func (r *Client) send(ctx context.Context, packetType int32, payload string, reassemble bool) (*response.RCON, error) {
+	r.mu.Lock()
+
 	if r.conn == nil {
-		return "", ErrNotConnected
+		r.mu.Unlock()
+
+		return nil, ErrNotConnected
 	}
 
 	if !r.authSuccess {
-		return "", ErrNotAuthenticated
+		r.mu.Unlock()
+
+		return nil, ErrNotAuthenticated
 	}
 
-	if err := r.Run(command); err != nil {
-		return "", err
+	conn := r.conn
+	request := &pendingRequest{
+		requestID:  r.nextRequestID(),
+		sentinelID: -1,
+		fragments:  make([]string, 0, 1),
+		result:     make(chan *response.RCON, 1),
 	}
 
-	for {
-		select {
-		case v := <-r.Messages:
+	if reassemble {
+		request.sentinelID = r.nextRequestID()
+
+		r.pending[request.sentinelID] = request
+	}
+
+	r.pending[request.requestID] = request
+
+	r.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+
+	// Command packet
+	// https://wiki.vg/RCON#2:_Command
+	if err := WritePacket(buf, Packet{RequestID: request.requestID, Type: packetType, Payload: payload}); err != nil {
+		r.removePending(request)
+
+		return nil, err
+	}
+
+	// Sentinel packet, which the server responds to after all packets of the command response
+	// https://wiki.vg/RCON#Fragmentation
+	if request.sentinelID != -1 {
+		if err := WritePacket(buf, Packet{RequestID: request.sentinelID, Type: PacketTypeResponse, Payload: ""}); err != nil {
+			r.removePending(request)
+
+			return nil, err
+		}
+	}
+
+	// A failed write may have sent part of the packets, so the connection cannot be used anymore.
+	if err := r.write(conn, buf.Bytes()); err != nil {
+		r.fail(conn, err)
+
+		return nil, fmt.Errorf("%w: %w", ErrConnectionLost, err)
+	}
+
+	select {
+	case v, ok := <-request.result:
+		{
+			if !ok {
+				return nil, ErrConnectionLost
+			}
+
 			return v, nil
-		case <-ctx.Done():
-			return "", context.DeadlineExceeded
+		}
+	case <-ctx.Done():
+		{
+			r.abandon(request)
+
+			return nil, mcerrors.Classify(ctx.Err())
 		}
 	}
 }
 
+// Done returns a channel that is closed when the connection to the server is closed, either by Close() or
+// because the connection was lost. Err returns the cause once the channel is closed.
+func (r *Client) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err returns nil while the client is connected. Once the connection is closed, it returns ErrClientClosed
+// if the connection was closed using Close(), or the error that caused the connection to be lost.
+func (r *Client) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		{
+			if r.readErr != nil {
+				return r.readErr
+			}
+
+			return ErrClientClosed
+		}
+	default:
+		return nil
+	}
+}
+
 // Close closes the connection to the server.
 func (r *Client) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.closeConn()
+}
+
+// closeConn closes the connection and fails all pending requests. The caller must hold the lock.
+func (r *Client) closeConn() error {
 	r.authSuccess = false
 	r.requestID = 0
 
-	if r.conn != nil {
-		if err := r.conn.Close(); err != nil {
-			return err
+	for requestID, request := range r.pending {
+		if requestID == request.requestID {
+			close(request.result)
 		}
+
+		delete(r.pending, requestID)
 	}
 
+	clear(r.abandoned)
+
+	r.queued = nil
+
+	if r.conn == nil {
+		return nil
+	}
+
+	close(r.done)
+
+	err := r.conn.Close()
+
 	r.conn = nil
 
-	return nil
+	return err
 }
 
-func (r *Client) readMessage() error {
-	// Command response packet
-	// https://wiki.vg/RCON#0:_Command_response
-	{
-		var (
-			packetLength int32
-			requestID    int32
-		)
+// fail closes the connection because of the error, unless it was already replaced or closed, and calls
+// the disconnect callback with the error.
+func (r *Client) fail(conn net.Conn, err error) {
+	r.mu.Lock()
 
-		// Length - int32
-		{
-			// TODO convert to binary.Read() for the rest of the package
-			data := make([]byte, 4)
+	if r.conn != conn {
+		r.mu.Unlock()
 
-			n, err := r.conn.Read(data)
+		return
+	}
 
-			if err != nil {
-				return err
-			}
+	r.readErr = mcerrors.Classify(err)
 
-			if n < 4 {
-				return nil
-			}
+	r.closeConn()
+
+	r.mu.Unlock()
+
+	if r.opts.OnDisconnect != nil {
+		r.opts.OnDisconnect(r.readErr)
+	}
+}
 
-			packetLength = int32(binary.LittleEndian.Uint16(data))
+func (r *Client) readLoop(conn net.Conn) {
+	for {
+		packet, err := r.readMessage(conn)
+
+		if err != nil {
+			r.fail(conn, err)
+
+			return
 		}
 
-		// Request ID - int32
-		{
-			if err := binary.Read(r.conn, binary.LittleEndian, &requestID); err != nil {
-				return err
+		r.mu.Lock()
+
+		request, ok := r.pending[packet.RequestID]
+
+		if ok {
+			r.completeFragment(request, packet.RequestID, packet.Payload)
+		} else if request, ok = r.abandoned[packet.RequestID]; ok {
+			r.discardFragment(request, packet.RequestID)
+		} else {
+			// The reader must never block on Messages, otherwise no responses to other commands would
+			// be received, so the message is queued and delivered by deliverLoop.
+			r.queued = append(r.queued, charset.Decode(packet.Payload))
+
+			select {
+			case r.notify <- struct{}{}:
+			default:
 			}
 		}
 
-		// Type - int32
-		{
-			var packetType int32
+		r.mu.Unlock()
+	}
+}
 
-			if err := binary.Read(r.conn, binary.LittleEndian, &packetType); err != nil {
-				return err
-			}
+// deliverLoop sends the queued messages to the Messages channel in the order they were received, until
+// the connection is closed.
+func (r *Client) deliverLoop(done <-chan struct{}) {
+	for {
+		r.mu.Lock()
 
-			if packetType != 0 {
-				return fmt.Errorf("rcon: received unexpected packet type (expected=0x00, received=0x%02X)", packetType)
+		if len(r.queued) < 1 {
+			r.mu.Unlock()
+
+			select {
+			case <-r.notify:
+				continue
+			case <-done:
+				return
 			}
 		}
 
-		// Payload - null-terminated string
-		{
-			data := make([]byte, packetLength-8)
+		message := r.queued[0]
+		r.queued = r.queued[1:]
 
-			n, err := r.conn.Read(data)
+		r.mu.Unlock()
 
-			if err != nil {
-				return err
-			}
+		select {
+		case r.Messages <- message:
+		case <-done:
+			return
+		}
+	}
+}
 
-			if n < 1 {
-				return nil
-			}
+// discardFragment drops a packet of a request that is no longer waited for, and forgets the request once
+// its last packet was received. The caller must hold the lock.
+func (r *Client) discardFragment(request *pendingRequest, requestID int32) {
+	if requestID == request.requestID && request.sentinelID != -1 {
+		return
+	}
+
+	delete(r.abandoned, request.requestID)
+	delete(r.abandoned, request.sentinelID)
+}
+
+// completeFragment adds the packet to the response of the pending request, and sends the response once
+// the last packet was received. The caller must hold the lock.
+func (r *Client) completeFragment(request *pendingRequest, requestID int32, message string) {
+	if requestID == request.requestID {
+		request.fragments = append(request.fragments, message)
 
-			r.Messages <- string(data)
+		if request.sentinelID != -1 {
+			return
 		}
 	}
 
-	return nil
+	delete(r.pending, request.requestID)
+	delete(r.pending, request.sentinelID)
+
+	// The response is decoded after reassembly, since responses are split into packets without regard
+	// for multi-byte characters.
+	request.result <- &response.RCON{
+		Output:    charset.Decode(strings.Join(request.fragments, "")),
+		Fragments: len(request.fragments),
+	}
+}
+
+// writePacket sends a single packet to the server.
+func (r *Client) writePacket(conn net.Conn, requestID, packetType int32, payload string) error {
+	buf := &bytes.Buffer{}
+
+	if err := WritePacket(buf, Packet{RequestID: requestID, Type: packetType, Payload: payload}); err != nil {
+		return err
+	}
+
+	return r.write(conn, buf.Bytes())
+}
+
+// write writes the data to the connection, making sure that packets written by multiple goroutines are
+// not interleaved.
+func (r *Client) write(conn net.Conn, data []byte) error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	_, err := conn.Write(data)
+
+	return err
 }
 
+// readMessage reads a single command response packet from the connection.
+// https://wiki.vg/RCON#0:_Command_response
+func (r *Client) readMessage(conn net.Conn) (*Packet, error) {
+	packet, err := ReadPacket(conn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if packet.Type != PacketTypeResponse {
+		return nil, mcerrors.NewUnexpectedPacket("rcon", int64(PacketTypeResponse), int64(packet.Type))
+	}
+
+	return packet, nil
+}
+
+// abandon stops waiting for the response to the request after it was sent. The response may still be
+// received later, so it is remembered until its last packet arrives and then discarded.
+func (r *Client) abandon(request *pendingRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The response was already received, or the connection was closed.
+	if r.pending[request.requestID] != request {
+		return
+	}
+
+	delete(r.pending, request.requestID)
+	delete(r.pending, request.sentinelID)
+
+	r.abandoned[request.requestID] = request
+
+	if request.sentinelID != -1 {
+		r.abandoned[request.sentinelID] = request
+	}
+}
+
+// removePending stops waiting for the response to the request.
+func (r *Client) removePending(request *pendingRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.pending, request.requestID)
+	delete(r.pending, request.sentinelID)
+}
+
+// nextRequestID returns the next request ID, skipping -1 which the server uses for failed logins. The
+// caller must hold the lock.
 func (r *Client) nextRequestID() int32 {
 	value := r.requestID
 
 	r.requestID++
 
+	if r.requestID < 0 {
+		r.requestID = 0
+	}
+
 	return value
 }
 
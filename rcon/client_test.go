@@ -0,0 +1,392 @@
+package rcon_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+const testPassword = "password"
+
+func TestExecuteConcurrent(t *testing.T) {
+	client := dialTestServer(t, func(command string) string {
+		return "reply to " + command
+	})
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+
+		go (func(i int) {
+			defer wg.Done()
+
+			command := fmt.Sprintf("say %d", i)
+
+			resp, err := client.Execute(context.Background(), command)
+
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			if resp != "reply to "+command {
+				t.Errorf("expected the reply to %q, received %q", command, resp)
+			}
+		})(i)
+	}
+
+	wg.Wait()
+}
+
+func TestExecuteFragmented(t *testing.T) {
+	output := strings.Repeat("0123456789", 1000)
+
+	client := dialTestServer(t, func(command string) string {
+		return output
+	})
+
+	resp, err := client.ExecuteResponse(context.Background(), "help")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Output != output || resp.Fragments != 3 {
+		t.Fatalf("expected 3 fragments of %d bytes, received %d fragments of %d bytes", len(output), resp.Fragments, len(resp.Output))
+	}
+}
+
+func TestExecuteFormatted(t *testing.T) {
+	client := dialTestServer(t, func(command string) string {
+		// Bukkit-family servers may send the section sign encoded as ISO-8859-1.
+		return "\xA7cDisabled\xA7r caf\xE9"
+	})
+
+	result, err := client.ExecuteFormatted(context.Background(), "plugins")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Raw != "\u00A7cDisabled café" || result.Clean != "Disabled café" {
+		t.Fatalf("unexpected formatted response: raw=%q, clean=%q", result.Raw, result.Clean)
+	}
+}
+
+func TestExecuteFragmentedUTF8(t *testing.T) {
+	// The odd prefix makes the fragment boundary fall within a two-byte character.
+	output := "a" + strings.Repeat("é", 3000)
+
+	client := dialTestServer(t, func(command string) string {
+		return output
+	})
+
+	resp, err := client.Execute(context.Background(), "help")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp != output {
+		t.Fatalf("expected %d bytes of valid UTF-8, received %d bytes", len(output), len(resp))
+	}
+}
+
+func TestExecuteContext(t *testing.T) {
+	client := dialTestServer(t, func(command string) string {
+		time.Sleep(time.Second)
+
+		return command
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+
+	defer cancel()
+
+	_, err := client.Execute(ctx, "list")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, received %v", err)
+	}
+
+	if !errors.Is(err, mcerrors.ErrTimeout) {
+		t.Fatalf("expected a timeout error, received %v", err)
+	}
+}
+
+func TestExecuteAfterTimeout(t *testing.T) {
+	client := dialTestServer(t, func(command string) string {
+		if command == "slow" {
+			time.Sleep(time.Millisecond * 200)
+		}
+
+		return "reply to " + command
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+
+	defer cancel()
+
+	if _, err := client.Execute(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, received %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second*5)
+
+	defer cancel()
+
+	resp, err := client.Execute(ctx, "list")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp != "reply to list" {
+		t.Fatalf("expected the reply to the second command, received %q", resp)
+	}
+}
+
+func TestExecuteWithoutReassembly(t *testing.T) {
+	output := strings.Repeat("0123456789", 1000)
+
+	addr := startTestServer(t, func(command string) string {
+		return output
+	})
+
+	client, err := rcon.Dial(addr.IP.String(), uint16(addr.Port), options.RCON{
+		Timeout:           time.Second,
+		DisableReassembly: true,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	if err := client.Login(testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	// The remaining fragments of each response are sent to Messages, which is never read from.
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+		resp, err := client.Execute(ctx, "help")
+
+		cancel()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp != output[:4096] {
+			t.Fatalf("expected the first fragment, received %d bytes", len(resp))
+		}
+	}
+}
+
+func TestRunMessages(t *testing.T) {
+	client := dialTestServer(t, func(command string) string {
+		return "reply to " + command
+	})
+
+	// Responses to Run are queued while Messages is not read from, without blocking Execute.
+	for i := 0; i < 100; i++ {
+		if err := client.Run(fmt.Sprintf("say %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	defer cancel()
+
+	if _, err := client.Execute(ctx, "list"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		select {
+		case message := <-client.Messages:
+			{
+				if expected := fmt.Sprintf("reply to say %d", i); message != expected {
+					t.Fatalf("expected %q, received %q", expected, message)
+				}
+			}
+		case <-ctx.Done():
+			t.Fatalf("expected 100 messages, received %d", i)
+		}
+	}
+}
+
+func TestDisconnect(t *testing.T) {
+	addr := startTestServer(t, func(command string) string {
+		return testDropConnection
+	})
+
+	disconnects := make(chan error, 2)
+
+	client, err := rcon.Dial(addr.IP.String(), uint16(addr.Port), options.RCON{
+		Timeout:      time.Second,
+		OnDisconnect: func(err error) { disconnects <- err },
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	if err := client.Login(testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	if client.Err() != nil {
+		t.Fatalf("expected no error while connected, received %v", client.Err())
+	}
+
+	client.Execute(context.Background(), "stop")
+
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected the client to be disconnected")
+	}
+
+	if err := client.Err(); err == nil || errors.Is(err, rcon.ErrClientClosed) {
+		t.Fatalf("expected the cause of the disconnect, received %v", err)
+	}
+
+	if err := <-disconnects; err == nil {
+		t.Fatal("expected the disconnect callback to receive the cause")
+	}
+}
+
+func TestCloseErr(t *testing.T) {
+	called := false
+
+	addr := startTestServer(t, func(command string) string {
+		return command
+	})
+
+	client, err := rcon.Dial(addr.IP.String(), uint16(addr.Port), options.RCON{
+		Timeout:      time.Second,
+		OnDisconnect: func(err error) { called = true },
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Login(testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	<-client.Done()
+
+	if err := client.Err(); !errors.Is(err, rcon.ErrClientClosed) {
+		t.Fatalf("expected the client to be closed, received %v", err)
+	}
+
+	if called {
+		t.Fatal("expected the disconnect callback to not be called after Close()")
+	}
+}
+
+func TestLoginInvalidPassword(t *testing.T) {
+	addr := startTestServer(t, func(command string) string {
+		t.Errorf("unexpected command: %q", command)
+
+		return ""
+	})
+
+	client, err := rcon.Dial(addr.IP.String(), uint16(addr.Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	if err := client.Login("wrong"); !errors.Is(err, rcon.ErrInvalidPassword) {
+		t.Fatalf("expected an invalid password error, received %v", err)
+	}
+}
+
+// dialTestServer starts a test server that responds to commands using the handler, and returns a client
+// that is logged in to it.
+func dialTestServer(t *testing.T, handler func(command string) string) *rcon.Client {
+	addr := startTestServer(t, handler)
+
+	client, err := rcon.Dial(addr.IP.String(), uint16(addr.Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { client.Close() })
+
+	if err := client.Login(testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+// startTestServer starts an RCON server on localhost that responds to commands using the handler.
+func startTestServer(t *testing.T, handler func(command string) string) *net.TCPAddr {
+	return startServer(t, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		return handler(command)
+	}))
+}
+
+// startSilentServer starts a server on localhost that accepts connections but never responds.
+func startSilentServer(t *testing.T) *net.TCPAddr {
+	return startSilentServerAt(t, "127.0.0.1:0")
+}
+
+// startSilentServerAt starts a server on the address that accepts connections but never responds.
+func startSilentServerAt(t *testing.T, address string) *net.TCPAddr {
+	listener, err := net.Listen("tcp", address)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	go (func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go (func() {
+				defer conn.Close()
+
+				io.Copy(io.Discard, conn)
+			})()
+		}
+	})()
+
+	return listener.Addr().(*net.TCPAddr)
+}
@@ -0,0 +1,296 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNullByte means a command contains a null byte, which the server treats as the end of the packet.
+	ErrNullByte = errors.New("rcon: command contains a null byte")
+	// ErrLineBreak means an argument contains a line break, which some servers treat as the start of
+	// another command.
+	ErrLineBreak = errors.New("rcon: argument contains a line break")
+	// ErrInvalidCommandName means a command name contains characters other than lowercase letters, digits
+	// and the characters _ : . and -.
+	ErrInvalidCommandName = errors.New("rcon: invalid command name")
+	// ErrInvalidUsername means a username is not 3 to 16 letters, digits and underscores.
+	ErrInvalidUsername = errors.New("rcon: invalid username")
+	// ErrInvalidUUID means a UUID is not 32 hexadecimal digits, with or without hyphens.
+	ErrInvalidUUID = errors.New("rcon: invalid UUID")
+	// ErrInvalidWord means an unquoted argument is empty or contains characters other than letters, digits
+	// and the characters _ - . and +.
+	ErrInvalidWord = errors.New("rcon: invalid unquoted argument")
+	// ErrInvalidIdentifier means a resource location is not an optional namespace and a path made of
+	// lowercase letters, digits and the characters _ - . and /.
+	ErrInvalidIdentifier = errors.New("rcon: invalid resource location")
+	// ErrInvalidSelector means a target selector is not one of @a, @e, @p, @r and @s.
+	ErrInvalidSelector = errors.New("rcon: invalid target selector")
+	// ErrSelectorInText means a text argument contains a target selector at the start of a word, which
+	// commands such as say and msg replace with the names of the matched entities.
+	ErrSelectorInText = errors.New("rcon: text argument contains a target selector")
+	// ErrArgumentAfterText means an argument was added after a text argument, which always reads the rest
+	// of the command.
+	ErrArgumentAfterText = errors.New("rcon: text argument must be the last argument")
+	// ErrCommandTooLong means a command is longer than the largest payload that fits in a packet.
+	ErrCommandTooLong = errors.New("rcon: command is longer than the maximum payload length")
+)
+
+var (
+	commandNameRegExp  = regexp.MustCompile(`^[a-z0-9_][a-z0-9_:.-]*$`)
+	usernameRegExp     = regexp.MustCompile(`^[A-Za-z0-9_]{3,16}$`)
+	uuidRegExp         = regexp.MustCompile(`^[0-9A-Fa-f]{8}-?[0-9A-Fa-f]{4}-?[0-9A-Fa-f]{4}-?[0-9A-Fa-f]{4}-?[0-9A-Fa-f]{12}$`)
+	wordRegExp         = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+	identifierRegExp   = regexp.MustCompile(`^(?:[a-z0-9_.-]+:)?[a-z0-9_./-]+$`)
+	selectorRegExp     = regexp.MustCompile(`^@[aeprs]$`)
+	textSelectorRegExp = regexp.MustCompile(`(?:^|\s)@[aenprs](?:$|\W)`)
+)
+
+// Command builds a command from arguments that are validated or escaped so that user input cannot change
+// the meaning of the command, such as by closing a quoted string or starting another command. The first
+// invalid argument is returned as an error by Build().
+type Command struct {
+	args []string
+	text bool
+	err  error
+}
+
+// NewCommand starts building a command with the name, without a leading slash.
+func NewCommand(name string) *Command {
+	c := &Command{
+		args: make([]string, 0),
+		text: false,
+		err:  nil,
+	}
+
+	if !commandNameRegExp.MatchString(name) {
+		c.err = fmt.Errorf("%w: %q", ErrInvalidCommandName, name)
+	}
+
+	return c.append(name, nil)
+}
+
+// Username adds a player name, which must be 3 to 16 letters, digits and underscores.
+func (c *Command) Username(name string) *Command {
+	if !usernameRegExp.MatchString(name) {
+		return c.append("", fmt.Errorf("%w: %q", ErrInvalidUsername, name))
+	}
+
+	return c.append(name, nil)
+}
+
+// UUID adds a UUID, which is written in the hyphenated lowercase form accepted by the server.
+func (c *Command) UUID(value string) *Command {
+	if !uuidRegExp.MatchString(value) {
+		return c.append("", fmt.Errorf("%w: %q", ErrInvalidUUID, value))
+	}
+
+	value = strings.ToLower(strings.ReplaceAll(value, "-", ""))
+
+	return c.append(fmt.Sprintf("%s-%s-%s-%s-%s", value[0:8], value[8:12], value[12:16], value[16:20], value[20:32]), nil)
+}
+
+// Word adds an unquoted argument, such as a subcommand or team name, which must be letters, digits and the
+// characters _ - . and +.
+func (c *Command) Word(value string) *Command {
+	if !wordRegExp.MatchString(value) {
+		return c.append("", fmt.Errorf("%w: %q", ErrInvalidWord, value))
+	}
+
+	return c.append(value, nil)
+}
+
+// Identifier adds a resource location, such as an item or block ID like minecraft:diamond.
+// https://minecraft.wiki/w/Resource_location
+func (c *Command) Identifier(value string) *Command {
+	if !identifierRegExp.MatchString(value) {
+		return c.append("", fmt.Errorf("%w: %q", ErrInvalidIdentifier, value))
+	}
+
+	return c.append(value, nil)
+}
+
+// Selector adds a target selector without any arguments, such as @a for all players.
+// https://minecraft.wiki/w/Target_selectors
+func (c *Command) Selector(value string) *Command {
+	if !selectorRegExp.MatchString(value) {
+		return c.append("", fmt.Errorf("%w: %q", ErrInvalidSelector, value))
+	}
+
+	return c.append(value, nil)
+}
+
+// Int adds an integer argument.
+func (c *Command) Int(value int64) *Command {
+	return c.append(strconv.FormatInt(value, 10), nil)
+}
+
+// Float adds a decimal argument.
+func (c *Command) Float(value float64) *Command {
+	return c.append(strconv.FormatFloat(value, 'f', -1, 64), nil)
+}
+
+// Quoted adds a quoted string argument, escaping any quotes and backslashes in the value.
+// https://minecraft.wiki/w/Argument_types#brigadier:string
+func (c *Command) Quoted(value string) *Command {
+	if err := checkArgument(value); err != nil {
+		return c.append("", err)
+	}
+
+	return c.append(`"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)+`"`, nil)
+}
+
+// Text adds an argument that is read until the end of the command, such as the message of the say
+// command. It must be the last argument of the command. Text containing a target selector such as @e or
+// @a[distance=..5] is rejected, since the server expands selectors within the messages of commands such as
+// say and msg. Only a selector at the start of the text or after whitespace that is not followed by a
+// letter, digit or underscore counts, so text such as admin@example.net or @server is allowed.
+func (c *Command) Text(value string) *Command {
+	if err := checkArgument(value); err != nil {
+		return c.append("", err)
+	}
+
+	if textSelectorRegExp.MatchString(value) {
+		return c.append("", fmt.Errorf("%w: %q", ErrSelectorInText, value))
+	}
+
+	c.append(value, nil)
+
+	c.text = true
+
+	return c
+}
+
+// JSON adds a value encoded as JSON, such as a text component for the tellraw command. Line breaks and
+// quotes within strings are escaped by the encoding, but null bytes are rejected like other arguments.
+// https://minecraft.wiki/w/Raw_JSON_text_format
+func (c *Command) JSON(value any) *Command {
+	buf := &bytes.Buffer{}
+
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return c.append("", err)
+	}
+
+	// The encoded value is decoded again so that the strings are checked the same way they are read by the
+	// server, including any strings produced by custom JSON encoders.
+	var decoded any
+
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		return c.append("", err)
+	}
+
+	if containsNullByte(decoded) {
+		return c.append("", ErrNullByte)
+	}
+
+	return c.append(strings.TrimSuffix(buf.String(), "\n"), nil)
+}
+
+// TextComponent adds a JSON text component containing the plain text, for commands such as tellraw and
+// title.
+func (c *Command) TextComponent(text string) *Command {
+	return c.JSON(map[string]string{"text": text})
+}
+
+// Build returns the command, or the first error from building it. An error is also returned if the
+// command contains a null byte or does not fit in a single packet. The vanilla server only reads packets
+// up to 1460 bytes long, so long commands may still be rejected by the server.
+func (c *Command) Build() (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	result := strings.Join(c.args, " ")
+
+	if err := checkCommand(result); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// MustBuild is like Build but panics if the command is invalid, for commands built from constant values.
+func (c *Command) MustBuild() string {
+	result, err := c.Build()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// append adds the argument to the command, recording the error if it is the first one.
+func (c *Command) append(arg string, err error) *Command {
+	if c.err != nil {
+		return c
+	}
+
+	if c.text {
+		err = ErrArgumentAfterText
+	}
+
+	if err != nil {
+		c.err = err
+
+		return c
+	}
+
+	c.args = append(c.args, arg)
+
+	return c
+}
+
+// checkArgument returns an error if the argument contains a null byte or a line break.
+func checkArgument(value string) error {
+	if strings.IndexByte(value, 0) >= 0 {
+		return ErrNullByte
+	}
+
+	if strings.ContainsAny(value, "\r\n") {
+		return ErrLineBreak
+	}
+
+	return nil
+}
+
+// containsNullByte returns whether any of the strings or object keys in the decoded JSON value contain a
+// null byte.
+func containsNullByte(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.IndexByte(v, 0) >= 0
+	case []any:
+		return slices.ContainsFunc(v, containsNullByte)
+	case map[string]any:
+		for key, item := range v {
+			if strings.IndexByte(key, 0) >= 0 || containsNullByte(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkCommand returns an error if the command contains a null byte or is longer than the largest payload.
+func checkCommand(command string) error {
+	if strings.IndexByte(command, 0) >= 0 {
+		return ErrNullByte
+	}
+
+	if len(command) > MaxPayloadLength {
+		return fmt.Errorf("%w: %d bytes", ErrCommandTooLong, len(command))
+	}
+
+	return nil
+}
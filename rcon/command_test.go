@@ -0,0 +1,88 @@
+package rcon_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		command  *rcon.Command
+		expected string
+	}{
+		{rcon.NewCommand("kick").Username("Notch").Text("Bye \"friend\""), `kick Notch Bye "friend"`},
+		{rcon.NewCommand("whitelist").Word("add").Username("jeb_"), "whitelist add jeb_"},
+		{rcon.NewCommand("op").UUID("069A79F444E94726A5BEFCA90E38AAF5"), "op 069a79f4-44e9-4726-a5be-fca90e38aaf5"},
+		{rcon.NewCommand("tp").Username("Notch").Float(1.5).Int(64).Float(-3), "tp Notch 1.5 64 -3"},
+		{rcon.NewCommand("give").Username("Notch").Identifier("minecraft:diamond").Int(1), "give Notch minecraft:diamond 1"},
+		{rcon.NewCommand("team").Word("add").Word("red").Quoted(`Red "Team" \ 1`), `team add red "Red \"Team\" \\ 1"`},
+		{rcon.NewCommand("tellraw").Selector("@a").TextComponent("hi\n<b> \"x\"} ] /op me"), `tellraw @a {"text":"hi\n<b> \"x\"} ] /op me"}`},
+		{rcon.NewCommand("tellraw").Selector("@a").TextComponent(`C:\u0000`), `tellraw @a {"text":"C:\\u0000"}`},
+		{rcon.NewCommand("say").Text("email me at notch@mojang.com"), "say email me at notch@mojang.com"},
+		{rcon.NewCommand("say").Text("contact admin@example.net or @server"), "say contact admin@example.net or @server"},
+	}
+
+	for _, test := range tests {
+		result, err := test.command.Build()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result != test.expected {
+			t.Fatalf("expected %q, received %q", test.expected, result)
+		}
+	}
+}
+
+func TestCommandInvalid(t *testing.T) {
+	tests := []struct {
+		command  *rcon.Command
+		expected error
+	}{
+		{rcon.NewCommand("say\nop"), rcon.ErrInvalidCommandName},
+		{rcon.NewCommand("kick").Username("Notch op Steve"), rcon.ErrInvalidUsername},
+		{rcon.NewCommand("kick").Username("ab"), rcon.ErrInvalidUsername},
+		{rcon.NewCommand("kick").Username("@a"), rcon.ErrInvalidUsername},
+		{rcon.NewCommand("team").Word("add").Word("red team"), rcon.ErrInvalidWord},
+		{rcon.NewCommand("give").Username("Notch").Identifier("minecraft:diamond 64"), rcon.ErrInvalidIdentifier},
+		{rcon.NewCommand("kill").Selector("@e[type=player]"), rcon.ErrInvalidSelector},
+		{rcon.NewCommand("op").UUID("069a79f4-44e9-4726-a5be-fca90e38aaf"), rcon.ErrInvalidUUID},
+		{rcon.NewCommand("say").Text("hi\nop Steve"), rcon.ErrLineBreak},
+		{rcon.NewCommand("say").Text("hi\x00"), rcon.ErrNullByte},
+		{rcon.NewCommand("team").Quoted("a\rb"), rcon.ErrLineBreak},
+		{rcon.NewCommand("tellraw").Selector("@a").TextComponent("hi\x00"), rcon.ErrNullByte},
+		{rcon.NewCommand("tellraw").Selector("@a").JSON(map[string]any{"extra": []any{"a\x00"}}), rcon.ErrNullByte},
+		{rcon.NewCommand("say").Text("hi @e"), rcon.ErrSelectorInText},
+		{rcon.NewCommand("say").Text("@p, come here"), rcon.ErrSelectorInText},
+		{rcon.NewCommand("msg").Username("Notch").Text("look at @a[distance=..5]"), rcon.ErrSelectorInText},
+		{rcon.NewCommand("say").Text("hi").Username("Notch"), rcon.ErrArgumentAfterText},
+		{rcon.NewCommand("say").Text(strings.Repeat("a", rcon.MaxPayloadLength)), rcon.ErrCommandTooLong},
+	}
+
+	for _, test := range tests {
+		if result, err := test.command.Build(); !errors.Is(err, test.expected) {
+			t.Fatalf("expected %v, received %q, %v", test.expected, result, err)
+		}
+	}
+}
+
+func TestExecuteNullByte(t *testing.T) {
+	client := dialTestServer(t, func(command string) string {
+		t.Errorf("unexpected command: %q", command)
+
+		return ""
+	})
+
+	if _, err := client.Execute(context.Background(), "say hi\x00op Steve"); !errors.Is(err, rcon.ErrNullByte) {
+		t.Fatalf("expected ErrNullByte, received %v", err)
+	}
+
+	if err := client.Run("say hi\x00"); !errors.Is(err, rcon.ErrNullByte) {
+		t.Fatalf("expected ErrNullByte, received %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package rcon
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+)
+
+const (
+	// MinPacketLength is the smallest length of a packet, which is a packet with an empty payload.
+	MinPacketLength = 10
+	// MaxPacketLength is the largest length of a packet accepted by the codec.
+	MaxPacketLength = 4110
+	// MaxPayloadLength is the largest payload that fits within MaxPacketLength.
+	MaxPayloadLength = MaxPacketLength - MinPacketLength
+)
+
+var (
+	// PacketTypeResponse is the type of a command response packet sent by the server.
+	PacketTypeResponse int32 = 0
+	// PacketTypeCommand is the type of a command packet sent by the client.
+	PacketTypeCommand int32 = 2
+	// PacketTypeAuthResponse is the type of a login response packet sent by the server.
+	PacketTypeAuthResponse int32 = 2
+	// PacketTypeLogin is the type of a login packet sent by the client.
+	PacketTypeLogin int32 = 3
+)
+
+var (
+	// ErrPacketLength means the length of a packet is outside of MinPacketLength and MaxPacketLength.
+	ErrPacketLength = errors.New("rcon: invalid packet length")
+	// ErrPacketTerminator means a packet does not end with the two null bytes.
+	ErrPacketTerminator = errors.New("rcon: packet is missing the null terminators")
+	// ErrPacketType means a packet has a type that is not used by the protocol.
+	ErrPacketType = errors.New("rcon: unknown packet type")
+)
+
+// Packet is a single RCON packet.
+// https://wiki.vg/RCON#Packet_Format
+type Packet struct {
+	RequestID int32
+	Type      int32
+	Payload   string
+}
+
+// ReadPacket reads a single packet from the reader, validating its length, type and terminators. Any
+// malformed packet returns a *mcerrors.ProtocolError with one of the packet errors as its kind.
+func ReadPacket(r io.Reader) (*Packet, error) {
+	var length int32
+
+	// Length - int32
+	{
+		data := make([]byte, 4)
+
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+
+		length = int32(binary.LittleEndian.Uint32(data))
+
+		if length < MinPacketLength || length > MaxPacketLength {
+			return nil, &mcerrors.ProtocolError{
+				Kind:     ErrPacketLength,
+				Field:    "length",
+				Expected: fmt.Sprintf("%d-%d", MinPacketLength, MaxPacketLength),
+				Received: length,
+				Message:  fmt.Sprintf("rcon: packet length is out of bounds (expected=%d-%d, received=%d)", MinPacketLength, MaxPacketLength, length),
+			}
+		}
+	}
+
+	data := make([]byte, length)
+
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	result := &Packet{
+		// Request ID - int32
+		RequestID: int32(binary.LittleEndian.Uint32(data[0:4])),
+		// Type - int32
+		Type: int32(binary.LittleEndian.Uint32(data[4:8])),
+	}
+
+	if result.Type != PacketTypeResponse && result.Type != PacketTypeCommand && result.Type != PacketTypeLogin {
+		return nil, &mcerrors.ProtocolError{
+			Kind:     ErrPacketType,
+			Field:    "packet type",
+			Expected: nil,
+			Received: result.Type,
+			Message:  fmt.Sprintf("rcon: received unknown packet type (received=0x%02X)", result.Type),
+		}
+	}
+
+	// Payload - null-terminated string
+	// Padding - byte
+	if data[length-2] != 0x00 || data[length-1] != 0x00 {
+		return nil, &mcerrors.ProtocolError{
+			Kind:     ErrPacketTerminator,
+			Field:    "payload",
+			Expected: []byte{0x00, 0x00},
+			Received: data[length-2:],
+			Message:  fmt.Sprintf("rcon: packet is missing the null terminators (received=% X)", data[length-2:]),
+		}
+	}
+
+	result.Payload = string(data[8 : length-2])
+
+	return result, nil
+}
+
+// WritePacket writes a single packet to the writer, returning an error if the payload is larger than
+// MaxPayloadLength. The packet is written using a single call to the writer.
+func WritePacket(w io.Writer, packet Packet) error {
+	if len(packet.Payload) > MaxPayloadLength {
+		return mcerrors.NewOversize("rcon", "payload", MaxPayloadLength, int64(len(packet.Payload)))
+	}
+
+	data := make([]byte, 0, MinPacketLength+4+len(packet.Payload))
+
+	// Length - int32
+	data = binary.LittleEndian.AppendUint32(data, uint32(MinPacketLength+len(packet.Payload)))
+
+	// Request ID - int32
+	data = binary.LittleEndian.AppendUint32(data, uint32(packet.RequestID))
+
+	// Type - int32
+	data = binary.LittleEndian.AppendUint32(data, uint32(packet.Type))
+
+	// Payload - null-terminated string
+	data = append(append(data, packet.Payload...), 0x00)
+
+	// Padding - byte
+	data = append(data, 0x00)
+
+	_, err := w.Write(data)
+
+	return err
+}
@@ -0,0 +1,130 @@
+package rcon_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+func TestReadPacket(t *testing.T) {
+	packet, err := rcon.ReadPacket(bytes.NewReader(encodeTestPacket(7, rcon.PacketTypeResponse, "There are 0 of a max of 20 players online: ")))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if packet.RequestID != 7 || packet.Type != rcon.PacketTypeResponse || packet.Payload != "There are 0 of a max of 20 players online: " {
+		t.Fatalf("unexpected packet: %+v", packet)
+	}
+}
+
+func TestReadPacketMalformed(t *testing.T) {
+	valid := encodeTestPacket(1, rcon.PacketTypeResponse, "hello")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"short length", []byte{0x01, 0x00}, io.ErrUnexpectedEOF},
+		{"length too small", []byte{0x09, 0x00, 0x00, 0x00}, rcon.ErrPacketLength},
+		{"length too large", []byte{0x0F, 0x10, 0x00, 0x00}, rcon.ErrPacketLength},
+		{"length above 16 bits", []byte{0x0A, 0x00, 0x01, 0x00}, rcon.ErrPacketLength},
+		{"truncated body", valid[:len(valid)-3], io.ErrUnexpectedEOF},
+		{"unknown type", encodeTestPacket(1, 5, ""), rcon.ErrPacketType},
+		{"missing terminator", append(valid[:len(valid)-1:len(valid)-1], 'x'), rcon.ErrPacketTerminator},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rcon.ReadPacket(bytes.NewReader(test.data))
+
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("expected %v, received %v", test.expected, err)
+			}
+
+			if test.expected != io.ErrUnexpectedEOF && !errors.Is(err, mcerrors.ErrProtocol) {
+				t.Fatalf("expected a protocol error, received %v", err)
+			}
+		})
+	}
+}
+
+func TestReadPacketPartialReads(t *testing.T) {
+	payload := strings.Repeat("a", 4000)
+
+	packet, err := rcon.ReadPacket(&oneByteReader{r: bytes.NewReader(encodeTestPacket(3, rcon.PacketTypeResponse, payload))})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if packet.Payload != payload {
+		t.Fatalf("expected a payload of %d bytes, received %d bytes", len(payload), len(packet.Payload))
+	}
+}
+
+func TestWritePacketOversize(t *testing.T) {
+	err := rcon.WritePacket(io.Discard, rcon.Packet{RequestID: 1, Type: rcon.PacketTypeCommand, Payload: strings.Repeat("a", rcon.MaxPayloadLength+1)})
+
+	if !errors.Is(err, mcerrors.ErrOversize) {
+		t.Fatalf("expected an oversize error, received %v", err)
+	}
+}
+
+func FuzzReadPacket(f *testing.F) {
+	f.Add(encodeTestPacket(0, rcon.PacketTypeResponse, ""))
+	f.Add(encodeTestPacket(-1, rcon.PacketTypeAuthResponse, ""))
+	f.Add(encodeTestPacket(12, rcon.PacketTypeCommand, "say hello"))
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		packet, err := rcon.ReadPacket(bytes.NewReader(data))
+
+		if err != nil {
+			return
+		}
+
+		buf := &bytes.Buffer{}
+
+		if err := rcon.WritePacket(buf, *packet); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(buf.Bytes(), data[:buf.Len()]) {
+			t.Fatalf("packet did not encode to the same data: %x != %x", buf.Bytes(), data[:buf.Len()])
+		}
+	})
+}
+
+// oneByteReader returns at most one byte per read.
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(p) < 1 {
+		return 0, nil
+	}
+
+	return r.r.Read(p[:1])
+}
+
+// encodeTestPacket encodes a packet independently of WritePacket.
+func encodeTestPacket(requestID, packetType int32, payload string) []byte {
+	buf := &bytes.Buffer{}
+
+	binary.Write(buf, binary.LittleEndian, int32(10+len(payload)))
+	binary.Write(buf, binary.LittleEndian, requestID)
+	binary.Write(buf, binary.LittleEndian, packetType)
+	buf.WriteString(payload)
+	buf.Write([]byte{0x00, 0x00})
+
+	return buf.Bytes()
+}
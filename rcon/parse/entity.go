@@ -0,0 +1,381 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	entityDataRegExp = regexp.MustCompile(`(?s)^(.+?) has the following entity data:\s*(.*)$`)
+)
+
+// EntityData is the output of the "data get entity" command. Data contains the parsed value, which is the
+// whole entity as a map[string]any or the value at the path given to the command.
+type EntityData struct {
+	Target string `json:"target"`
+	SNBT   string `json:"snbt"`
+	Data   any    `json:"data"`
+}
+
+// ParseEntityData parses the output of the "data get entity" command.
+func ParseEntityData(output string) (*EntityData, error) {
+	match := entityDataRegExp.FindStringSubmatch(clean(output))
+
+	if match == nil {
+		return nil, unrecognized("data get entity", output)
+	}
+
+	data, err := ParseSNBT(match[2])
+
+	if err != nil {
+		return nil, unrecognized("data get entity", output)
+	}
+
+	return &EntityData{
+		Target: match[1],
+		SNBT:   match[2],
+		Data:   data,
+	}, nil
+}
+
+// ParseSNBT parses stringified NBT data into Go values. Compounds are returned as map[string]any, lists
+// as []any, arrays as []int8, []int32 or []int64, and numbers as the Go type matching their suffix, which
+// is int32 for integers and float64 for decimals without a suffix.
+// https://minecraft.wiki/w/NBT_format#SNBT_format
+func ParseSNBT(value string) (any, error) {
+	p := &snbtParser{
+		data: value,
+		pos:  0,
+	}
+
+	result, err := p.parseValue()
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipWhitespace()
+
+	if p.pos < len(p.data) {
+		return nil, p.errorf("unexpected data after value")
+	}
+
+	return result, nil
+}
+
+type snbtParser struct {
+	data string
+	pos  int
+}
+
+func (p *snbtParser) parseValue() (any, error) {
+	p.skipWhitespace()
+
+	if p.pos >= len(p.data) {
+		return nil, p.errorf("unexpected end of data")
+	}
+
+	switch p.data[p.pos] {
+	case '{':
+		return p.parseCompound()
+	case '[':
+		return p.parseList()
+	case '"', '\'':
+		return p.parseQuotedString()
+	default:
+		return p.parseUnquoted()
+	}
+}
+
+func (p *snbtParser) parseCompound() (any, error) {
+	result := make(map[string]any)
+
+	p.pos++
+
+	if p.consume('}') {
+		return result, nil
+	}
+
+	for {
+		p.skipWhitespace()
+
+		var (
+			key string
+			err error
+		)
+
+		if p.pos < len(p.data) && (p.data[p.pos] == '"' || p.data[p.pos] == '\'') {
+			key, err = p.parseQuotedString()
+		} else {
+			key = p.readUnquoted()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(key) < 1 || !p.consume(':') {
+			return nil, p.errorf("expected a key followed by a colon")
+		}
+
+		value, err := p.parseValue()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = value
+
+		if p.consume('}') {
+			return result, nil
+		}
+
+		if !p.consume(',') {
+			return nil, p.errorf("expected a comma or closing brace")
+		}
+	}
+}
+
+func (p *snbtParser) parseList() (any, error) {
+	p.pos++
+
+	// Arrays are lists prefixed by their type and a semicolon, such as [I; 1, 2, 3].
+	if p.pos+1 < len(p.data) && p.data[p.pos+1] == ';' && strings.IndexByte("BIL", p.data[p.pos]) >= 0 {
+		arrayType := p.data[p.pos]
+
+		p.pos += 2
+
+		values, err := p.parseElements()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return toArray(arrayType, values, p)
+	}
+
+	return p.parseElements()
+}
+
+func (p *snbtParser) parseElements() ([]any, error) {
+	result := make([]any, 0)
+
+	if p.consume(']') {
+		return result, nil
+	}
+
+	for {
+		value, err := p.parseValue()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, value)
+
+		if p.consume(']') {
+			return result, nil
+		}
+
+		if !p.consume(',') {
+			return nil, p.errorf("expected a comma or closing bracket")
+		}
+	}
+}
+
+func (p *snbtParser) parseQuotedString() (string, error) {
+	quote := p.data[p.pos]
+	result := strings.Builder{}
+
+	p.pos++
+
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+
+		p.pos++
+
+		switch c {
+		case quote:
+			return result.String(), nil
+		case '\\':
+			{
+				if p.pos >= len(p.data) {
+					return "", p.errorf("unexpected end of data in string")
+				}
+
+				result.WriteByte(p.data[p.pos])
+
+				p.pos++
+			}
+		default:
+			result.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *snbtParser) parseUnquoted() (any, error) {
+	value := p.readUnquoted()
+
+	if len(value) < 1 {
+		return nil, p.errorf("unexpected character %q", p.data[p.pos])
+	}
+
+	switch strings.ToLower(value) {
+	case "true":
+		return int8(1), nil
+	case "false":
+		return int8(0), nil
+	}
+
+	if number, ok := parseNumber(value); ok {
+		return number, nil
+	}
+
+	return value, nil
+}
+
+func (p *snbtParser) readUnquoted() string {
+	start := p.pos
+
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && strings.IndexByte("_-.+", c) < 0 {
+			break
+		}
+
+		p.pos++
+	}
+
+	return p.data[start:p.pos]
+}
+
+// consume skips any whitespace and the character if it is next, returning whether it was.
+func (p *snbtParser) consume(c byte) bool {
+	p.skipWhitespace()
+
+	if p.pos < len(p.data) && p.data[p.pos] == c {
+		p.pos++
+
+		return true
+	}
+
+	return false
+}
+
+func (p *snbtParser) skipWhitespace() {
+	for p.pos < len(p.data) && strings.IndexByte(" \t\r\n", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *snbtParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("parse: invalid SNBT at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// parseNumber parses a number with an optional type suffix.
+func parseNumber(value string) (any, bool) {
+	suffix := value[len(value)-1]
+	digits := value[:len(value)-1]
+
+	switch suffix {
+	case 'b', 'B':
+		{
+			v, err := strconv.ParseInt(digits, 10, 8)
+
+			return int8(v), err == nil
+		}
+	case 's', 'S':
+		{
+			v, err := strconv.ParseInt(digits, 10, 16)
+
+			return int16(v), err == nil
+		}
+	case 'l', 'L':
+		{
+			v, err := strconv.ParseInt(digits, 10, 64)
+
+			return v, err == nil
+		}
+	case 'f', 'F':
+		{
+			v, err := strconv.ParseFloat(digits, 32)
+
+			return float32(v), err == nil
+		}
+	case 'd', 'D':
+		{
+			v, err := strconv.ParseFloat(digits, 64)
+
+			return v, err == nil
+		}
+	}
+
+	if v, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return int32(v), true
+	}
+
+	if v, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, ".eE") {
+		return v, true
+	}
+
+	return nil, false
+}
+
+// toArray converts the elements of a typed array into a slice of the type.
+func toArray(arrayType byte, values []any, p *snbtParser) (any, error) {
+	switch arrayType {
+	case 'B':
+		{
+			result := make([]int8, len(values))
+
+			for i, value := range values {
+				v, ok := value.(int8)
+
+				if !ok {
+					return nil, p.errorf("expected a byte in byte array")
+				}
+
+				result[i] = v
+			}
+
+			return result, nil
+		}
+	case 'I':
+		{
+			result := make([]int32, len(values))
+
+			for i, value := range values {
+				v, ok := value.(int32)
+
+				if !ok {
+					return nil, p.errorf("expected an int in int array")
+				}
+
+				result[i] = v
+			}
+
+			return result, nil
+		}
+	default:
+		{
+			result := make([]int64, len(values))
+
+			for i, value := range values {
+				v, ok := value.(int64)
+
+				if !ok {
+					return nil, p.errorf("expected a long in long array")
+				}
+
+				result[i] = v
+			}
+
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package parse turns the output of common RCON commands into typed values. The parsers accept the
+// wording of vanilla, Paper, Spigot and Forge servers, and return an *UnrecognizedError for any output
+// they do not understand.
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrUnrecognized means the output of a command did not match any known format.
+	ErrUnrecognized = errors.New("parse: unrecognized output")
+)
+
+var (
+	formattingCodeRegExp = regexp.MustCompile("§[0-9A-FK-ORXa-fk-orx]")
+	listSeparatorRegExp  = regexp.MustCompile(`\s*,\s*|\s+and\s+`)
+)
+
+// UnrecognizedError means the output of a command did not match any known format. The output is kept so
+// it can be logged or shown to the user as it is.
+type UnrecognizedError struct {
+	Command string
+	Output  string
+}
+
+// Error returns the message of the error.
+func (e *UnrecognizedError) Error() string {
+	return fmt.Sprintf("parse: unrecognized %s output: %q", e.Command, e.Output)
+}
+
+// Is returns whether the target is ErrUnrecognized, which all unrecognized errors match.
+func (e *UnrecognizedError) Is(target error) bool {
+	return target == ErrUnrecognized
+}
+
+func unrecognized(command, output string) error {
+	return &UnrecognizedError{
+		Command: command,
+		Output:  output,
+	}
+}
+
+// clean removes the formatting codes and surrounding whitespace from the output.
+func clean(output string) string {
+	return strings.TrimSpace(formattingCodeRegExp.ReplaceAllString(output, ""))
+}
+
+// splitNames splits a list of names separated by commas or the word "and", ignoring empty entries.
+func splitNames(value string) []string {
+	result := make([]string, 0)
+
+	for _, name := range listSeparatorRegExp.Split(strings.TrimSpace(value), -1) {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
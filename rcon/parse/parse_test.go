@@ -0,0 +1,205 @@
+package parse_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/rcon/parse"
+)
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected *parse.List
+	}{
+		{
+			"vanilla",
+			"There are 2 of a max of 20 players online: Notch, jeb_",
+			&parse.List{Online: 2, Max: 20, Players: []parse.ListPlayer{{Name: "Notch"}, {Name: "jeb_"}}},
+		},
+		{
+			"vanilla empty",
+			"There are 0 of a max of 20 players online: ",
+			&parse.List{Online: 0, Max: 20, Players: []parse.ListPlayer{}},
+		},
+		{
+			"vanilla uuids",
+			"There are 1 of a max of 20 players online: Notch (069a79f4-44e9-4726-a5be-fca90e38aaf5)",
+			&parse.List{Online: 1, Max: 20, Players: []parse.ListPlayer{{Name: "Notch", UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5"}}},
+		},
+		{
+			"legacy",
+			"There are 2/10 players online:\nNotch, jeb_",
+			&parse.List{Online: 2, Max: 10, Players: []parse.ListPlayer{{Name: "Notch"}, {Name: "jeb_"}}},
+		},
+		{
+			"essentials",
+			"§6There are §c2§6 out of maximum §c50§6 players online.\n§6Admins§r: [AFK]§7Notch§r\n§6Default§r: jeb_",
+			&parse.List{Online: 2, Max: 50, Players: []parse.ListPlayer{{Name: "Notch"}, {Name: "jeb_"}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := parse.ParseList(test.output)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Fatalf("expected %+v, received %+v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseWhitelist(t *testing.T) {
+	tests := map[string][]string{
+		"There are 3 whitelisted players: Notch, jeb_, Dinnerbone":         {"Notch", "jeb_", "Dinnerbone"},
+		"There are 2 (out of 4 seen) whitelisted players:\nNotch and jeb_": {"Notch", "jeb_"},
+		"There are no whitelisted players":                                 {},
+		"There are 3 whitelisted player(s): Notch, jeb_ and Dinnerbone":    {"Notch", "jeb_", "Dinnerbone"},
+		"Unknown or incomplete command, see below for error":               nil,
+		"There are 1 whitelisted players: Notch":                           {"Notch"},
+	}
+
+	for output, expected := range tests {
+		result, err := parse.ParseWhitelist(output)
+
+		if expected == nil {
+			if !errors.Is(err, parse.ErrUnrecognized) {
+				t.Errorf("expected %q to be unrecognized, received %v", output, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", output, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %q, received %q", expected, result)
+		}
+	}
+}
+
+func TestParseBanlist(t *testing.T) {
+	output := "There are 2 ban(s):Notch was banned by Server: Banned by an operator.127.0.0.1 was banned by jeb_: spam"
+
+	result, err := parse.ParseBanlist(output)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []parse.Ban{
+		{Target: "Notch", Source: "Server", Reason: "Banned by an operator."},
+		{Target: "127.0.0.1", Source: "jeb_", Reason: "spam"},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %+v, received %+v", expected, result)
+	}
+
+	if result, err := parse.ParseBanlist("There are no bans"); err != nil || len(result) != 0 {
+		t.Fatalf("expected no bans, received %+v (%v)", result, err)
+	}
+}
+
+func TestParseTPS(t *testing.T) {
+	paper, err := parse.ParseTPS("§6TPS from last 1m, 5m, 15m: §a*20.0, §a19.98, §a19.5")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paper.OneMinute != 20 || paper.FiveMinutes != 19.98 || paper.FifteenMinutes != 19.5 {
+		t.Fatalf("unexpected Paper TPS: %+v", paper)
+	}
+
+	forge, err := parse.ParseTPS("Dim minecraft:overworld (minecraft:overworld): Mean tick time: 0.579 ms. Mean TPS: 20.000\nOverall: Mean tick time: 1.250 ms. Mean TPS: 20.000")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if forge.OneMinute != 20 || forge.MeanTickTime != time.Microsecond*1250 {
+		t.Fatalf("unexpected Forge TPS: %+v", forge)
+	}
+}
+
+func TestParseMSPT(t *testing.T) {
+	result, err := parse.ParseMSPT("§6Server tick times §e(§7avg§e/§7min§e/§7max§e)§6 from last 5s§7,§6 10s§7,§6 1m§e:\n§6◴ §a2.1§7/§a1.2§7/§a5.3§e, §a2.0§7/§a1.1§7/§a6.0§e, §a1.9§7/§a0.9§7/§a7.2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.FiveSeconds.Average != time.Microsecond*2100 || result.OneMinute.Max != time.Microsecond*7200 {
+		t.Fatalf("unexpected MSPT: %+v", result)
+	}
+}
+
+func TestParseWorld(t *testing.T) {
+	if value, err := parse.ParseTimeQuery("The time is 13000"); err != nil || value != 13000 {
+		t.Errorf("unexpected time: %d (%v)", value, err)
+	}
+
+	if value, err := parse.ParseSeed("Seed: [-4172144997902289642]"); err != nil || value != -4172144997902289642 {
+		t.Errorf("unexpected seed: %d (%v)", value, err)
+	}
+
+	if value, err := parse.ParseSeed("Seed: 12345"); err != nil || value != 12345 {
+		t.Errorf("unexpected legacy seed: %d (%v)", value, err)
+	}
+
+	if value, err := parse.ParseDifficulty("The difficulty is Hard"); err != nil || value != parse.DifficultyHard {
+		t.Errorf("unexpected difficulty: %s (%v)", value, err)
+	}
+
+	if value, err := parse.ParseWorldborder("The world border is currently 59999968 block(s) wide"); err != nil || value != 59999968 {
+		t.Errorf("unexpected world border: %f (%v)", value, err)
+	}
+
+	if _, err := parse.ParseSeed("Unknown or incomplete command, see below for error"); !errors.Is(err, parse.ErrUnrecognized) {
+		t.Errorf("expected an unrecognized error, received %v", err)
+	}
+}
+
+func TestParseEntityData(t *testing.T) {
+	result, err := parse.ParseEntityData(`Notch has the following entity data: {Health: 20.0f, Pos: [0.5d, 64.0d, -2.5d], UUID: [I; 1, -2, 3, 4], Inventory: [{Slot: 0b, id: "minecraft:stone", count: 64}], CustomName: 'It\'s me', OnGround: 1b}`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, ok := result.Data.(map[string]any)
+
+	if !ok || result.Target != "Notch" {
+		t.Fatalf("unexpected entity data: %+v", result)
+	}
+
+	expected := map[string]any{
+		"Health":     float32(20),
+		"Pos":        []any{0.5, 64.0, -2.5},
+		"UUID":       []int32{1, -2, 3, 4},
+		"Inventory":  []any{map[string]any{"Slot": int8(0), "id": "minecraft:stone", "count": int32(64)}},
+		"CustomName": "It's me",
+		"OnGround":   int8(1),
+	}
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Fatalf("expected %#v, received %#v", expected, data)
+	}
+
+	if _, err := parse.ParseEntityData("No entity was found"); !errors.Is(err, parse.ErrUnrecognized) {
+		t.Fatalf("expected an unrecognized error, received %v", err)
+	}
+}
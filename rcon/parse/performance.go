@@ -0,0 +1,125 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	tpsRegExp      = regexp.MustCompile(`(?i)tps from last 1m, 5m, 15m:\s*\*?([\d.]+),\s*\*?([\d.]+),\s*\*?([\d.]+)`)
+	forgeTPSRegExp = regexp.MustCompile(`(?i)overall\s*:\s*mean tick time:\s*([\d.]+)\s*ms\.?\s*mean tps:\s*([\d.]+)`)
+	msptRegExp     = regexp.MustCompile(`(?i)server tick times \(avg/min/max\) from last 5s, 10s, 1m:\s*\S*\s*([\d.]+)/([\d.]+)/([\d.]+),\s*([\d.]+)/([\d.]+)/([\d.]+),\s*([\d.]+)/([\d.]+)/([\d.]+)`)
+)
+
+// TPS is the output of the tps command of Paper and Spigot servers, or the forge tps command of Forge
+// servers. Forge only reports the mean over the last 100 ticks, which is used for all three averages.
+type TPS struct {
+	OneMinute      float64       `json:"one_minute"`
+	FiveMinutes    float64       `json:"five_minutes"`
+	FifteenMinutes float64       `json:"fifteen_minutes"`
+	MeanTickTime   time.Duration `json:"mean_tick_time"`
+}
+
+// MSPT is the output of the mspt command of Paper servers.
+type MSPT struct {
+	FiveSeconds TickTimes `json:"five_seconds"`
+	TenSeconds  TickTimes `json:"ten_seconds"`
+	OneMinute   TickTimes `json:"one_minute"`
+}
+
+// TickTimes is the average, shortest and longest tick time over a period.
+type TickTimes struct {
+	Average time.Duration `json:"average"`
+	Min     time.Duration `json:"min"`
+	Max     time.Duration `json:"max"`
+}
+
+// ParseTPS parses the output of the tps command of Paper and Spigot servers, or the forge tps command of
+// Forge servers. Values capped at 20 and marked with an asterisk by Paper are returned without it.
+func ParseTPS(output string) (*TPS, error) {
+	text := clean(output)
+
+	if match := tpsRegExp.FindStringSubmatch(text); match != nil {
+		values, ok := parseFloats(match[1:])
+
+		if !ok {
+			return nil, unrecognized("tps", output)
+		}
+
+		return &TPS{
+			OneMinute:      values[0],
+			FiveMinutes:    values[1],
+			FifteenMinutes: values[2],
+			MeanTickTime:   0,
+		}, nil
+	}
+
+	if match := forgeTPSRegExp.FindStringSubmatch(text); match != nil {
+		values, ok := parseFloats(match[1:])
+
+		if !ok {
+			return nil, unrecognized("tps", output)
+		}
+
+		return &TPS{
+			OneMinute:      values[1],
+			FiveMinutes:    values[1],
+			FifteenMinutes: values[1],
+			MeanTickTime:   milliseconds(values[0]),
+		}, nil
+	}
+
+	return nil, unrecognized("tps", output)
+}
+
+// ParseMSPT parses the output of the mspt command of Paper servers.
+func ParseMSPT(output string) (*MSPT, error) {
+	match := msptRegExp.FindStringSubmatch(clean(output))
+
+	if match == nil {
+		return nil, unrecognized("mspt", output)
+	}
+
+	values, ok := parseFloats(match[1:])
+
+	if !ok {
+		return nil, unrecognized("mspt", output)
+	}
+
+	times := make([]TickTimes, 3)
+
+	for i := range times {
+		times[i] = TickTimes{
+			Average: milliseconds(values[i*3]),
+			Min:     milliseconds(values[i*3+1]),
+			Max:     milliseconds(values[i*3+2]),
+		}
+	}
+
+	return &MSPT{
+		FiveSeconds: times[0],
+		TenSeconds:  times[1],
+		OneMinute:   times[2],
+	}, nil
+}
+
+func parseFloats(values []string) ([]float64, bool) {
+	result := make([]float64, len(values))
+
+	for i, value := range values {
+		v, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			return nil, false
+		}
+
+		result[i] = v
+	}
+
+	return result, true
+}
+
+func milliseconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Millisecond))
+}
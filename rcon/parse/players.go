@@ -0,0 +1,168 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	listRegExp      = regexp.MustCompile(`(?i)there (?:are|is) (\d+)\s*(?:of a max(?:imum)? of|out of (?:a )?max(?:imum)?|/)\s*(\d+) players? online[.:]?`)
+	listUUIDRegExp  = regexp.MustCompile(`^(.+?)\s*\(([0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12})\)$`)
+	listTagRegExp   = regexp.MustCompile(`^(?:\[[^\]]*\]\s*)+`)
+	listGroupRegExp = regexp.MustCompile(`^[^:,]+:\s+`)
+
+	whitelistRegExp      = regexp.MustCompile(`(?i)there (?:are|is) (\d+)(?: \(out of \d+ seen\))? whitelisted players?(?:\(s\))?:?`)
+	whitelistEmptyRegExp = regexp.MustCompile(`(?i)there are no whitelisted players`)
+
+	banlistRegExp      = regexp.MustCompile(`(?i)there (?:are|is) (\d+) bans?(?:\(s\))?:?`)
+	banlistEmptyRegExp = regexp.MustCompile(`(?i)there are no bans`)
+	banMarkerRegExp    = regexp.MustCompile(` was banned by `)
+	banEntryRegExp     = regexp.MustCompile(`(?s)(\S+) was banned by (.+?): (.*)`)
+	banTargetRegExp    = regexp.MustCompile(`(?:(?:\d{1,3}\.){3}\d{1,3}|[A-Za-z0-9_]{1,16})$`)
+)
+
+// List is the output of the list command.
+type List struct {
+	Online  int          `json:"online"`
+	Max     int          `json:"max"`
+	Players []ListPlayer `json:"players"`
+}
+
+// ListPlayer is a single player in the output of the list command. The UUID is only known if the command
+// was run as "list uuids".
+type ListPlayer struct {
+	Name string `json:"name"`
+	UUID string `json:"uuid,omitempty"`
+}
+
+// Ban is a single entry in the output of the banlist command.
+type Ban struct {
+	Target string `json:"target"`
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// ParseList parses the output of the list command, including the "list uuids" variant and the grouped
+// output of Essentials.
+func ParseList(output string) (*List, error) {
+	text := clean(output)
+
+	match := listRegExp.FindStringSubmatchIndex(text)
+
+	if match == nil {
+		return nil, unrecognized("list", output)
+	}
+
+	online, _ := strconv.Atoi(text[match[2]:match[3]])
+	max, _ := strconv.Atoi(text[match[4]:match[5]])
+
+	result := &List{
+		Online:  online,
+		Max:     max,
+		Players: make([]ListPlayer, 0, online),
+	}
+
+	for _, line := range strings.Split(text[match[1]:], "\n") {
+		// Essentials lists the players of each group on a separate line, prefixed by the group name.
+		line = listGroupRegExp.ReplaceAllString(strings.TrimSpace(line), "")
+
+		for _, name := range splitNames(line) {
+			// Plugins may prefix names with tags such as [AFK] or [HIDDEN].
+			name = strings.TrimSpace(listTagRegExp.ReplaceAllString(name, ""))
+
+			if len(name) < 1 {
+				continue
+			}
+
+			player := ListPlayer{
+				Name: name,
+				UUID: "",
+			}
+
+			if uuid := listUUIDRegExp.FindStringSubmatch(name); uuid != nil {
+				player.Name = uuid[1]
+				player.UUID = uuid[2]
+			}
+
+			result.Players = append(result.Players, player)
+		}
+	}
+
+	return result, nil
+}
+
+// ParseWhitelist parses the output of the "whitelist list" command into the names of the whitelisted
+// players.
+func ParseWhitelist(output string) ([]string, error) {
+	text := clean(output)
+
+	if whitelistEmptyRegExp.MatchString(text) {
+		return []string{}, nil
+	}
+
+	match := whitelistRegExp.FindStringIndex(text)
+
+	if match == nil {
+		return nil, unrecognized("whitelist list", output)
+	}
+
+	return splitNames(strings.ReplaceAll(text[match[1]:], "\n", ",")), nil
+}
+
+// ParseBanlist parses the output of the banlist command. Vanilla servers send the entries without any
+// separator between them over RCON, so the target of each entry is found by the format of player names
+// and IP addresses.
+func ParseBanlist(output string) ([]Ban, error) {
+	text := clean(output)
+
+	if banlistEmptyRegExp.MatchString(text) {
+		return []Ban{}, nil
+	}
+
+	match := banlistRegExp.FindStringSubmatchIndex(text)
+
+	if match == nil {
+		return nil, unrecognized("banlist", output)
+	}
+
+	count, _ := strconv.Atoi(text[match[2]:match[3]])
+	body := text[match[1]:]
+	result := make([]Ban, 0, count)
+
+	// Each entry starts at its target, which is directly before the next "was banned by" text.
+	markers := banMarkerRegExp.FindAllStringIndex(body, -1)
+	starts := make([]int, 0, len(markers))
+
+	for _, marker := range markers {
+		target := banTargetRegExp.FindStringIndex(body[:marker[0]])
+
+		if target == nil {
+			return nil, unrecognized("banlist", output)
+		}
+
+		starts = append(starts, target[0])
+	}
+
+	for i, start := range starts {
+		end := len(body)
+
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+
+		entry := banEntryRegExp.FindStringSubmatch(strings.TrimSpace(body[start:end]))
+
+		if entry == nil {
+			return nil, unrecognized("banlist", output)
+		}
+
+		result = append(result, Ban{
+			Target: entry[1],
+			Source: strings.TrimSpace(entry[2]),
+			Reason: strings.TrimSpace(entry[3]),
+		})
+	}
+
+	return result, nil
+}
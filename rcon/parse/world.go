@@ -0,0 +1,104 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	timeRegExp        = regexp.MustCompile(`(?i)^the time is (-?\d+)`)
+	seedRegExp        = regexp.MustCompile(`(?i)^seed:\s*\[?(-?\d+)\]?`)
+	difficultyRegExp  = regexp.MustCompile(`(?i)^(?:the difficulty is|the difficulty has been set to|difficulty:)\s*(\w+)`)
+	worldborderRegExp = regexp.MustCompile(`(?i)^the world border is currently (-?[\d.]+) blocks?(?:\(s\))? wide`)
+)
+
+// Difficulty is the difficulty of a world.
+type Difficulty string
+
+var (
+	// DifficultyPeaceful is the peaceful difficulty.
+	DifficultyPeaceful Difficulty = "peaceful"
+	// DifficultyEasy is the easy difficulty.
+	DifficultyEasy Difficulty = "easy"
+	// DifficultyNormal is the normal difficulty.
+	DifficultyNormal Difficulty = "normal"
+	// DifficultyHard is the hard difficulty.
+	DifficultyHard Difficulty = "hard"
+)
+
+// ParseTimeQuery parses the output of the "time query" command, which is the day time, game time or day
+// depending on the argument of the command.
+func ParseTimeQuery(output string) (int64, error) {
+	match := timeRegExp.FindStringSubmatch(clean(output))
+
+	if match == nil {
+		return 0, unrecognized("time query", output)
+	}
+
+	value, err := strconv.ParseInt(match[1], 10, 64)
+
+	if err != nil {
+		return 0, unrecognized("time query", output)
+	}
+
+	return value, nil
+}
+
+// ParseSeed parses the output of the seed command.
+func ParseSeed(output string) (int64, error) {
+	match := seedRegExp.FindStringSubmatch(clean(output))
+
+	if match == nil {
+		return 0, unrecognized("seed", output)
+	}
+
+	value, err := strconv.ParseInt(match[1], 10, 64)
+
+	if err != nil {
+		return 0, unrecognized("seed", output)
+	}
+
+	return value, nil
+}
+
+// ParseDifficulty parses the output of the difficulty command, both when querying and when setting the
+// difficulty.
+func ParseDifficulty(output string) (Difficulty, error) {
+	match := difficultyRegExp.FindStringSubmatch(clean(output))
+
+	if match == nil {
+		return "", unrecognized("difficulty", output)
+	}
+
+	switch Difficulty(strings.ToLower(match[1])) {
+	case DifficultyPeaceful:
+		return DifficultyPeaceful, nil
+	case DifficultyEasy:
+		return DifficultyEasy, nil
+	case DifficultyNormal:
+		return DifficultyNormal, nil
+	case DifficultyHard:
+		return DifficultyHard, nil
+	default:
+		return "", unrecognized("difficulty", output)
+	}
+}
+
+// ParseWorldborder parses the output of the "worldborder get" command into the width of the world border
+// in blocks.
+func ParseWorldborder(output string) (float64, error) {
+	match := worldborderRegExp.FindStringSubmatch(clean(output))
+
+	if match == nil {
+		return 0, unrecognized("worldborder get", output)
+	}
+
+	value, err := strconv.ParseFloat(match[1], 64)
+
+	if err != nil {
+		return 0, unrecognized("worldborder get", output)
+	}
+
+	return value, nil
+}
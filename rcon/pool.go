@@ -0,0 +1,440 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+var (
+	// ErrPoolClosed means a command was executed on a pool that was already closed.
+	ErrPoolClosed = errors.New("rcon: pool is closed")
+)
+
+var (
+	defaultPoolOptions = options.RCONPool{
+		RCON:                defaultOptions,
+		Size:                4,
+		MaxInFlight:         4,
+		HealthCheckInterval: time.Second * 30,
+		HealthCheckCommand:  "",
+	}
+)
+
+// Pool keeps multiple authenticated connections to the same server and hands out commands across them.
+// The server processes the commands of each connection one at a time, so spreading commands across
+// connections allows them to be processed at the same time. It is safe to use from multiple goroutines
+// at once.
+type Pool struct {
+	hostname string
+	port     uint16
+	password string
+	opts     options.RCONPool
+	sem      chan struct{}
+	mu       sync.Mutex
+	conns    []*poolConn
+	changed  chan struct{}
+	closed   chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// poolConn is a single connection in the pool, with a nil client while it is not connected.
+type poolConn struct {
+	client       *Client
+	inFlight     int
+	lastUsed     time.Time
+	reconnecting bool
+}
+
+// NewPool opens the connections of the pool and logs in to each of them using the password. An error is
+// returned if any of the connections fail.
+func NewPool(ctx context.Context, hostname string, port uint16, password string, options ...options.RCONPool) (*Pool, error) {
+	opts := parsePoolOptions(options...)
+
+	// The context of the pool stops the background health checks and reconnects once the pool is closed.
+	poolCtx, cancel := context.WithCancel(context.Background())
+
+	p := &Pool{
+		hostname: hostname,
+		port:     port,
+		password: password,
+		opts:     opts,
+		sem:      make(chan struct{}, opts.MaxInFlight),
+		conns:    make([]*poolConn, 0, opts.Size),
+		changed:  make(chan struct{}),
+		closed:   make(chan struct{}),
+		ctx:      poolCtx,
+		cancel:   cancel,
+	}
+
+	for i := 0; i < opts.Size; i++ {
+		client, err := dialAndLogin(ctx, hostname, port, password, opts.RCON)
+
+		if err != nil {
+			for _, conn := range p.conns {
+				conn.client.Close()
+			}
+
+			cancel()
+
+			return nil, err
+		}
+
+		p.conns = append(p.conns, &poolConn{
+			client:       client,
+			inFlight:     0,
+			lastUsed:     time.Now(),
+			reconnecting: false,
+		})
+	}
+
+	if opts.HealthCheckInterval > 0 {
+		p.wg.Add(1)
+
+		go p.healthCheckLoop()
+	}
+
+	return p, nil
+}
+
+// Execute runs the command on the least busy connection and waits for the response to that command.
+func (p *Pool) Execute(ctx context.Context, command string) (string, error) {
+	result, err := p.ExecuteResponse(ctx, command)
+
+	if err != nil {
+		return "", err
+	}
+
+	return result.Output, nil
+}
+
+// ExecuteFormatted runs the command on the server and waits for the response to that command, parsing
+// the formatting codes in the response.
+func (p *Pool) ExecuteFormatted(ctx context.Context, command string) (*formatting.Result, error) {
+	result, err := p.ExecuteResponse(ctx, command)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return formatting.Parse(result.Output)
+}
+
+// ExecuteResponse runs the command on the least busy connection and waits for the full response to that
+// command. It waits for a free slot first if the maximum amount of in-flight commands is reached.
+func (p *Pool) ExecuteResponse(ctx context.Context, command string) (*response.RCON, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	defer (func() { <-p.sem })()
+
+	conn, client, err := p.acquire(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer p.release(conn)
+
+	return client.ExecuteResponse(ctx, command)
+}
+
+// Close closes all connections of the pool. Commands that are waiting for a response return
+// ErrConnectionLost, and any later commands return ErrPoolClosed.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+
+	select {
+	case <-p.closed:
+		{
+			p.mu.Unlock()
+
+			return nil
+		}
+	default:
+	}
+
+	close(p.closed)
+
+	p.mu.Unlock()
+
+	p.cancel()
+
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var result error
+
+	for _, conn := range p.conns {
+		if conn.client == nil {
+			continue
+		}
+
+		if err := conn.client.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+
+	return result
+}
+
+// acquire returns the connected connection with the fewest in-flight commands and its client, reconnecting
+// a disconnected connection if none are connected.
+func (p *Pool) acquire(ctx context.Context) (*poolConn, *Client, error) {
+	for {
+		p.mu.Lock()
+
+		select {
+		case <-p.closed:
+			{
+				p.mu.Unlock()
+
+				return nil, nil, ErrPoolClosed
+			}
+		default:
+		}
+
+		var (
+			best         *poolConn
+			disconnected *poolConn
+		)
+
+		for _, conn := range p.conns {
+			if !conn.connected() {
+				if disconnected == nil && !conn.reconnecting {
+					disconnected = conn
+				}
+
+				continue
+			}
+
+			if best == nil || conn.inFlight < best.inFlight {
+				best = conn
+			}
+		}
+
+		if best != nil {
+			best.inFlight++
+			best.lastUsed = time.Now()
+
+			client := best.client
+
+			p.mu.Unlock()
+
+			return best, client, nil
+		}
+
+		if disconnected == nil {
+			changed := p.changed
+
+			p.mu.Unlock()
+
+			select {
+			case <-changed:
+				continue
+			case <-p.closed:
+				return nil, nil, ErrPoolClosed
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+
+		disconnected.reconnecting = true
+
+		p.mu.Unlock()
+
+		if err := p.reconnect(ctx, disconnected); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// release marks the command on the connection as finished.
+func (p *Pool) release(conn *poolConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn.inFlight--
+	conn.lastUsed = time.Now()
+}
+
+// reconnect replaces the client of a connection that was marked as reconnecting.
+func (p *Pool) reconnect(ctx context.Context, conn *poolConn) error {
+	client, err := dialAndLogin(ctx, p.hostname, p.port, p.password, p.opts.RCON)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn.reconnecting = false
+
+	// Callers waiting for the reconnect are woken up whether or not it succeeded, so that they can use the
+	// new client or try to reconnect themselves.
+	defer p.broadcast()
+
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-p.closed:
+		{
+			client.Close()
+
+			return ErrPoolClosed
+		}
+	default:
+	}
+
+	if conn.client != nil {
+		conn.client.Close()
+	}
+
+	conn.client = client
+	conn.lastUsed = time.Now()
+
+	return nil
+}
+
+// broadcast wakes up all callers that are waiting for a connection to change. The caller must hold the
+// lock.
+func (p *Pool) broadcast() {
+	close(p.changed)
+
+	p.changed = make(chan struct{})
+}
+
+// healthCheckLoop periodically checks the connections that have been idle for the health check interval,
+// and reconnects any connections that were lost.
+func (p *Pool) healthCheckLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.HealthCheckInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.closed:
+			return
+		}
+
+		p.mu.Lock()
+
+		idle := make(map[*poolConn]*Client)
+		disconnected := make([]*poolConn, 0)
+
+		for _, conn := range p.conns {
+			switch {
+			case !conn.connected() && !conn.reconnecting:
+				{
+					conn.reconnecting = true
+
+					disconnected = append(disconnected, conn)
+				}
+			case conn.connected() && conn.inFlight == 0 && time.Since(conn.lastUsed) >= p.opts.HealthCheckInterval:
+				{
+					conn.inFlight++
+
+					idle[conn] = conn.client
+				}
+			}
+		}
+
+		p.mu.Unlock()
+
+		for conn, client := range idle {
+			p.healthCheck(conn, client)
+		}
+
+		for _, conn := range disconnected {
+			p.reconnect(p.ctx, conn)
+		}
+	}
+}
+
+// healthCheck checks a connection that was reserved by the caller, closing it if it does not respond. The
+// check counts against the maximum amount of in-flight commands like any other command.
+func (p *Pool) healthCheck(conn *poolConn, client *Client) {
+	defer p.release(conn)
+
+	select {
+	case p.sem <- struct{}{}:
+	case <-p.ctx.Done():
+		return
+	}
+
+	defer (func() { <-p.sem })()
+
+	timeout := p.opts.RCON.Timeout
+
+	if timeout <= 0 {
+		timeout = defaultOptions.Timeout
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, timeout)
+
+	defer cancel()
+
+	var err error
+
+	if len(p.opts.HealthCheckCommand) > 0 {
+		_, err = client.ExecuteResponse(ctx, p.opts.HealthCheckCommand)
+	} else {
+		err = client.ping(ctx)
+	}
+
+	if err != nil {
+		client.Close()
+	}
+}
+
+// connected returns whether the connection has a client that has not been disconnected.
+func (c *poolConn) connected() bool {
+	if c.client == nil {
+		return false
+	}
+
+	select {
+	case <-c.client.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+func parsePoolOptions(opts ...options.RCONPool) options.RCONPool {
+	if len(opts) < 1 {
+		return defaultPoolOptions
+	}
+
+	result := opts[0]
+
+	if result.Size < 1 {
+		result.Size = defaultPoolOptions.Size
+	}
+
+	if result.MaxInFlight < 1 {
+		result.MaxInFlight = result.Size
+	}
+
+	if result.HealthCheckInterval == 0 {
+		result.HealthCheckInterval = defaultPoolOptions.HealthCheckInterval
+	}
+
+	return result
+}
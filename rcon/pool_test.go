@@ -0,0 +1,249 @@
+package rcon_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+func TestPoolDispatch(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	addr := startTestServer(t, func(command string) string {
+		value := running.Add(1)
+
+		defer running.Add(-1)
+
+		for {
+			current := maxRunning.Load()
+
+			if value <= current || maxRunning.CompareAndSwap(current, value) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond * 20)
+
+		return "reply to " + command
+	})
+
+	pool, err := rcon.NewPool(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword, options.RCONPool{
+		Size:        4,
+		MaxInFlight: 4,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer pool.Close()
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go (func() {
+			defer wg.Done()
+
+			resp, err := pool.Execute(context.Background(), "tp Notch 0 64 0")
+
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			if resp != "reply to tp Notch 0 64 0" {
+				t.Errorf("unexpected response: %q", resp)
+			}
+		})()
+	}
+
+	wg.Wait()
+
+	if value := maxRunning.Load(); value < 2 || value > 4 {
+		t.Fatalf("expected between 2 and 4 commands to run at once, received %d", value)
+	}
+}
+
+func TestPoolReconnect(t *testing.T) {
+	addr := startTestServer(t, func(command string) string {
+		if command == "stop" {
+			return testDropConnection
+		}
+
+		return "reply to " + command
+	})
+
+	pool, err := rcon.NewPool(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword, options.RCONPool{
+		RCON: options.RCON{Timeout: time.Second},
+		Size: 1,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer pool.Close()
+
+	if _, err := pool.Execute(context.Background(), "stop"); !errors.Is(err, rcon.ErrConnectionLost) {
+		t.Fatalf("expected the connection to be lost, received %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	defer cancel()
+
+	for {
+		resp, err := pool.Execute(ctx, "list")
+
+		// The pool may still see the old connection as connected until its reader notices the closed
+		// connection, so commands are retried until the pool has reconnected.
+		if errors.Is(err, rcon.ErrConnectionLost) || errors.Is(err, rcon.ErrNotConnected) {
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp != "reply to list" {
+			t.Fatalf("unexpected response: %q", resp)
+		}
+
+		break
+	}
+}
+
+func TestPoolClosed(t *testing.T) {
+	addr := startTestServer(t, func(command string) string {
+		return command
+	})
+
+	pool, err := rcon.NewPool(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := pool.Execute(context.Background(), "list"); !errors.Is(err, rcon.ErrPoolClosed) {
+		t.Fatalf("expected the pool to be closed, received %v", err)
+	}
+}
+
+func TestPoolReconnectFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	server := rcon.NewServer(testPassword, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		return command
+	}))
+
+	go server.Serve(listener)
+
+	pool, err := rcon.NewPool(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword, options.RCONPool{
+		RCON:                options.RCON{Timeout: time.Millisecond * 200},
+		Size:                1,
+		MaxInFlight:         2,
+		HealthCheckInterval: -1,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer pool.Close()
+
+	// The server is replaced by one that never responds, so that reconnecting fails after the timeout.
+	server.Close()
+
+	startSilentServerAt(t, addr.String())
+
+	time.Sleep(time.Millisecond * 100)
+
+	errs := make(chan error, 2)
+
+	for i := 0; i < 2; i++ {
+		go (func() {
+			_, err := pool.Execute(context.Background(), "list")
+
+			errs <- err
+		})()
+
+		time.Sleep(time.Millisecond * 20)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			{
+				if err == nil {
+					t.Fatal("expected the command to fail")
+				}
+			}
+		case <-time.After(time.Second * 3):
+			t.Fatal("expected the waiting command to return after the reconnect failed")
+		}
+	}
+}
+
+func TestPoolCloseDuringReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	server := rcon.NewServer(testPassword, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		return command
+	}))
+
+	go server.Serve(listener)
+
+	// Without a timeout, the health check reconnect to a server that never responds only stops once the
+	// pool is closed.
+	pool, err := rcon.NewPool(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword, options.RCONPool{
+		RCON:                options.RCON{Timeout: 0},
+		Size:                1,
+		HealthCheckInterval: time.Millisecond * 20,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Close()
+
+	startSilentServerAt(t, addr.String())
+
+	time.Sleep(time.Millisecond * 200)
+
+	closed := make(chan error, 1)
+
+	go (func() { closed <- pool.Close() })()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second * 3):
+		t.Fatal("expected the pool to close while reconnecting")
+	}
+}
@@ -0,0 +1,322 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+var (
+	// ErrClientClosed means the client was closed using Close(), or the resilient client gave up
+	// reconnecting to the server, in which case it is wrapped together with the error of the last attempt.
+	ErrClientClosed = errors.New("rcon: client is closed")
+)
+
+var (
+	defaultResilientOptions = options.RCONResilient{
+		RCON:              defaultOptions,
+		InitialBackoff:    time.Second,
+		MaxBackoff:        time.Second * 30,
+		BackoffMultiplier: 2,
+		MaxAttempts:       0,
+		InFlight:          options.RCONInFlightFail,
+	}
+)
+
+// ResilientClient is an RCON client that reconnects to the server and logs in again with the same password
+// whenever the connection is lost, such as when the server restarts. It is safe to use from multiple
+// goroutines at once.
+type ResilientClient struct {
+	hostname     string
+	port         uint16
+	password     string
+	opts         options.RCONResilient
+	mu           sync.Mutex
+	client       *Client
+	connected    chan struct{}
+	disconnected chan struct{}
+	closed       chan struct{}
+	err          error
+}
+
+// DialResilient connects to the server and logs in using the password, returning a client that
+// automatically reconnects when the connection is lost. An error is returned if the first connection or
+// login fails.
+func DialResilient(ctx context.Context, hostname string, port uint16, password string, options ...options.RCONResilient) (*ResilientClient, error) {
+	r := &ResilientClient{
+		hostname:     hostname,
+		port:         port,
+		password:     password,
+		opts:         parseResilientOptions(options...),
+		client:       nil,
+		connected:    make(chan struct{}),
+		disconnected: make(chan struct{}),
+		closed:       make(chan struct{}),
+		err:          nil,
+	}
+
+	client, err := dialAndLogin(ctx, r.hostname, r.port, r.password, r.opts.RCON)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.setClient(client)
+
+	return r, nil
+}
+
+// Execute runs the command on the server and waits for the response to that command, waiting for the
+// client to reconnect first if the connection was lost.
+func (r *ResilientClient) Execute(ctx context.Context, command string) (string, error) {
+	result, err := r.ExecuteResponse(ctx, command)
+
+	if err != nil {
+		return "", err
+	}
+
+	return result.Output, nil
+}
+
+// ExecuteFormatted runs the command on the server and waits for the response to that command, parsing
+// the formatting codes in the response.
+func (r *ResilientClient) ExecuteFormatted(ctx context.Context, command string) (*formatting.Result, error) {
+	result, err := r.ExecuteResponse(ctx, command)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return formatting.Parse(result.Output)
+}
+
+// ExecuteResponse runs the command on the server and waits for the full response to that command. Commands
+// that were sent but lost their connection before the response was received are sent again or return
+// ErrConnectionLost, depending on the in-flight policy in the options.
+func (r *ResilientClient) ExecuteResponse(ctx context.Context, command string) (*response.RCON, error) {
+	for {
+		client, err := r.waitClient(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := client.ExecuteResponse(ctx, command)
+
+		if err == nil || ctx.Err() != nil {
+			return result, err
+		}
+
+		// The command was never sent, so it is always safe to send it again after reconnecting.
+		if errors.Is(err, ErrNotConnected) || errors.Is(err, ErrNotAuthenticated) {
+			continue
+		}
+
+		if errors.Is(err, ErrConnectionLost) && r.opts.InFlight == options.RCONInFlightReplay {
+			continue
+		}
+
+		return nil, err
+	}
+}
+
+// Close closes the connection to the server and stops reconnecting.
+func (r *ResilientClient) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	select {
+	case <-r.closed:
+		return nil
+	default:
+	}
+
+	r.err = ErrClientClosed
+
+	close(r.closed)
+
+	if r.client == nil {
+		return nil
+	}
+
+	return r.client.Close()
+}
+
+// waitClient returns the current connection, waiting for the client to reconnect if needed.
+func (r *ResilientClient) waitClient(ctx context.Context) (*Client, error) {
+	for {
+		r.mu.Lock()
+
+		client, connected, disconnected, err := r.client, r.connected, r.disconnected, r.err
+
+		r.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		// A client that was lost is kept until watch() notices it, so the lost client is waited for
+		// instead of being returned again.
+		if client != nil {
+			select {
+			case <-client.Done():
+				connected = disconnected
+			default:
+				return client, nil
+			}
+		}
+
+		select {
+		case <-connected:
+		case <-r.closed:
+		case <-ctx.Done():
+			return nil, mcerrors.Classify(ctx.Err())
+		}
+	}
+}
+
+// setClient makes the client the current connection and waits for it to be lost in the background.
+func (r *ResilientClient) setClient(client *Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	select {
+	case <-r.closed:
+		{
+			client.Close()
+
+			return
+		}
+	default:
+	}
+
+	r.client = client
+	r.disconnected = make(chan struct{})
+
+	close(r.connected)
+
+	go r.watch(client, r.disconnected)
+}
+
+// watch waits for the connection to be lost and then reconnects to the server, closing disconnected once
+// the lost client was removed.
+func (r *ResilientClient) watch(client *Client, disconnected chan struct{}) {
+	<-client.Done()
+
+	r.mu.Lock()
+
+	select {
+	case <-r.closed:
+		{
+			r.mu.Unlock()
+
+			return
+		}
+	default:
+	}
+
+	r.client = nil
+	r.connected = make(chan struct{})
+
+	close(disconnected)
+
+	r.mu.Unlock()
+
+	err := client.Err()
+
+	if r.opts.OnDisconnect != nil {
+		r.opts.OnDisconnect(err)
+	}
+
+	r.reconnect()
+}
+
+// reconnect attempts to connect to the server until it succeeds, the client is closed, the maximum
+// amount of attempts is reached or the password is no longer accepted.
+func (r *ResilientClient) reconnect() {
+	var (
+		backoff time.Duration = r.opts.InitialBackoff
+		lastErr error         = nil
+	)
+
+	for attempt := 1; r.opts.MaxAttempts < 1 || attempt <= r.opts.MaxAttempts; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-r.closed:
+			return
+		}
+
+		backoff = min(time.Duration(float64(backoff)*r.opts.BackoffMultiplier), r.opts.MaxBackoff)
+
+		client, err := dialAndLogin(context.Background(), r.hostname, r.port, r.password, r.opts.RCON)
+
+		if err == nil {
+			r.setClient(client)
+
+			if r.opts.OnConnect != nil {
+				r.opts.OnConnect()
+			}
+
+			return
+		}
+
+		lastErr = err
+
+		if r.opts.OnReconnectFailed != nil {
+			r.opts.OnReconnectFailed(attempt, err)
+		}
+
+		if errors.Is(err, mcerrors.ErrAuth) {
+			r.fail(err)
+
+			return
+		}
+	}
+
+	r.fail(fmt.Errorf("%w: %w", ErrClientClosed, lastErr))
+}
+
+// fail closes the client with the error, which is returned by any later commands.
+func (r *ResilientClient) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	select {
+	case <-r.closed:
+		return
+	default:
+	}
+
+	r.err = err
+
+	close(r.closed)
+}
+
+func parseResilientOptions(opts ...options.RCONResilient) options.RCONResilient {
+	if len(opts) < 1 {
+		return defaultResilientOptions
+	}
+
+	result := opts[0]
+
+	if result.InitialBackoff <= 0 {
+		result.InitialBackoff = defaultResilientOptions.InitialBackoff
+	}
+
+	if result.MaxBackoff <= 0 {
+		result.MaxBackoff = defaultResilientOptions.MaxBackoff
+	}
+
+	if result.BackoffMultiplier < 1 {
+		result.BackoffMultiplier = defaultResilientOptions.BackoffMultiplier
+	}
+
+	return result
+}
@@ -0,0 +1,171 @@
+package rcon_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+func TestResilientReconnect(t *testing.T) {
+	var (
+		disconnects atomic.Int32
+		connects    atomic.Int32
+	)
+
+	addr := startTestServer(t, func(command string) string {
+		if command == "stop" {
+			return testDropConnection
+		}
+
+		return "reply to " + command
+	})
+
+	client, err := rcon.DialResilient(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword, options.RCONResilient{
+		RCON:           options.RCON{Timeout: time.Second},
+		InitialBackoff: time.Millisecond * 10,
+		InFlight:       options.RCONInFlightFail,
+		OnConnect:      func() { connects.Add(1) },
+		OnDisconnect:   func(err error) { disconnects.Add(1) },
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	if _, err := client.Execute(context.Background(), "stop"); !errors.Is(err, rcon.ErrConnectionLost) {
+		t.Fatalf("expected the connection to be lost, received %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	defer cancel()
+
+	resp, err := client.Execute(ctx, "list")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp != "reply to list" {
+		t.Fatalf("unexpected response: %q", resp)
+	}
+
+	if disconnects.Load() != 1 || connects.Load() != 1 {
+		t.Fatalf("expected 1 disconnect and 1 reconnect, received %d and %d", disconnects.Load(), connects.Load())
+	}
+}
+
+func TestResilientReplay(t *testing.T) {
+	var dropped atomic.Bool
+
+	addr := startTestServer(t, func(command string) string {
+		if !dropped.Swap(true) {
+			return testDropConnection
+		}
+
+		return "reply to " + command
+	})
+
+	client, err := rcon.DialResilient(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword, options.RCONResilient{
+		RCON:           options.RCON{Timeout: time.Second},
+		InitialBackoff: time.Millisecond * 10,
+		InFlight:       options.RCONInFlightReplay,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	defer cancel()
+
+	resp, err := client.Execute(ctx, "give Notch diamond")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp != "reply to give Notch diamond" {
+		t.Fatalf("unexpected response: %q", resp)
+	}
+}
+
+func TestLoginContext(t *testing.T) {
+	listener := startSilentServer(t)
+
+	client, err := rcon.DialContext(context.Background(), listener.IP.String(), uint16(listener.Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+
+	defer cancel()
+
+	if err := client.LoginContext(ctx, testPassword); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, received %v", err)
+	}
+}
+
+func TestResilientGiveUp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	server := rcon.NewServer(testPassword, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		return command
+	}))
+
+	go server.Serve(listener)
+
+	client, err := rcon.DialResilient(context.Background(), addr.IP.String(), uint16(addr.Port), testPassword, options.RCONResilient{
+		RCON:           options.RCON{Timeout: time.Second},
+		InitialBackoff: time.Millisecond * 10,
+		MaxAttempts:    2,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	// Closing the server refuses any further connections, so every reconnect attempt fails.
+	server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	defer cancel()
+
+	// The command may still be sent on the old connection before the client notices that it was lost.
+	for {
+		_, err = client.Execute(ctx, "list")
+
+		if !errors.Is(err, rcon.ErrConnectionLost) {
+			break
+		}
+	}
+
+	if !errors.Is(err, rcon.ErrClientClosed) || !errors.Is(err, mcerrors.ErrConnectionRefused) {
+		t.Fatalf("expected the client to give up with the cause, received %v", err)
+	}
+}
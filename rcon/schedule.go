@@ -0,0 +1,254 @@
+package rcon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	cronMonthNames = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	cronDayNames   = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+	cronShorthands = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// Schedule decides when a scheduled job runs.
+type Schedule interface {
+	// Next returns the first time the job runs after the time, or the zero time if it never runs again.
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule runs at every multiple of the interval since the Unix epoch, so the run times stay the
+// same across restarts of the program.
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every returns a schedule that runs at a fixed interval. The runs are aligned to multiples of the interval
+// since the Unix epoch, so an interval of one hour runs at the start of every hour.
+func Every(interval time.Duration) Schedule {
+	return intervalSchedule{
+		interval: max(interval, time.Second),
+	}
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	elapsed := after.Sub(time.Unix(0, 0))
+
+	return time.Unix(0, 0).Add(elapsed - elapsed%s.interval + s.interval).In(after.Location())
+}
+
+// timesSchedule runs once at each of a list of times.
+type timesSchedule struct {
+	times []time.Time
+}
+
+// At returns a schedule that runs once at each of the times.
+func At(times ...time.Time) Schedule {
+	return timesSchedule{
+		times: times,
+	}
+}
+
+func (s timesSchedule) Next(after time.Time) time.Time {
+	var result time.Time
+
+	for _, t := range s.times {
+		if t.After(after) && (result.IsZero() || t.Before(result)) {
+			result = t
+		}
+	}
+
+	return result
+}
+
+// cronSchedule is a parsed cron expression, with a bit set for each allowed value of each field.
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// anyDay is true if either day field is a wildcard, in which case both day fields must match instead
+	// of either of them.
+	anyDay bool
+}
+
+// ParseCron parses a standard five-field cron expression (minute, hour, day of month, month and day of
+// week) into a schedule. Fields support wildcards, lists, ranges, steps, and month and day names, and the
+// shorthands @yearly, @monthly, @weekly, @daily and @hourly are accepted. The schedule is evaluated in the
+// location of the time passed to Next.
+func ParseCron(expression string) (Schedule, error) {
+	expression = strings.TrimSpace(expression)
+
+	if value, ok := cronShorthands[strings.ToLower(expression)]; ok {
+		expression = value
+	}
+
+	fields := strings.Fields(expression)
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("rcon: cron expression must have 5 fields, received %d: %q", len(fields), expression)
+	}
+
+	var (
+		result cronSchedule
+		err    error
+	)
+
+	if result.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+
+	if result.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+
+	if result.dayOfMonth, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+
+	if result.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, err
+	}
+
+	if result.dayOfWeek, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, err
+	}
+
+	// Both 0 and 7 are Sunday.
+	if result.dayOfWeek&(1<<7) != 0 {
+		result.dayOfWeek |= 1
+	}
+
+	result.anyDay = strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*")
+
+	return result, nil
+}
+
+func (s cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+wrap:
+	for t.Before(limit) {
+		for s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+
+		for !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+
+			if t.Day() == 1 {
+				continue wrap
+			}
+		}
+
+		for s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+
+			if t.Hour() == 0 {
+				continue wrap
+			}
+		}
+
+		for s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+
+			if t.Minute() == 0 {
+				continue wrap
+			}
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDay {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps into a bit set.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var result uint64
+
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepValue, hasStep := strings.Cut(part, "/")
+
+		step := 1
+
+		if hasStep {
+			value, err := strconv.Atoi(stepValue)
+
+			if err != nil || value < 1 {
+				return 0, fmt.Errorf("rcon: invalid step in cron field: %q", field)
+			}
+
+			step = value
+		}
+
+		start, end := min, max
+
+		if valueRange != "*" {
+			startValue, endValue, isRange := strings.Cut(valueRange, "-")
+
+			value, err := parseCronValue(startValue, min, max, names)
+
+			if err != nil {
+				return 0, fmt.Errorf("rcon: invalid cron field: %q", field)
+			}
+
+			start, end = value, value
+
+			if isRange {
+				if end, err = parseCronValue(endValue, min, max, names); err != nil || end < start {
+					return 0, fmt.Errorf("rcon: invalid range in cron field: %q", field)
+				}
+			} else if hasStep {
+				end = max
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			result |= 1 << uint(i)
+		}
+	}
+
+	return result, nil
+}
+
+func parseCronValue(value string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(value)
+
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("rcon: cron value out of range: %q", value)
+	}
+
+	return v, nil
+}
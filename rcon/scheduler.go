@@ -0,0 +1,530 @@
+package rcon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+// missedRunTolerance is how late a run may start before it is treated as missed, such as when the
+// program was not running or the system was asleep at the scheduled time.
+const missedRunTolerance = time.Minute
+
+var (
+	// ErrSchedulerRunning means Run() was called on a scheduler that is already running.
+	ErrSchedulerRunning = errors.New("rcon: scheduler is already running")
+	// ErrInvalidJob means a job was added without a name, schedule or commands.
+	ErrInvalidJob = errors.New("rcon: job must have a name, a schedule and at least one command")
+	// ErrDuplicateJob means a job was added with the same name as a job already in the scheduler.
+	ErrDuplicateJob = errors.New("rcon: a job with the same name already exists")
+)
+
+var (
+	defaultSchedulerOptions = options.RCONScheduler{
+		Timeout:    time.Second * 30,
+		StateFile:  "",
+		MaxCatchUp: 100,
+		OnError:    nil,
+	}
+)
+
+// MissedRunPolicy is what the scheduler does with the runs of a job that were missed, either because the
+// program was not running at the scheduled time or because the system was asleep.
+type MissedRunPolicy int
+
+var (
+	// MissedRunSkip skips all missed runs and waits for the next scheduled run.
+	MissedRunSkip MissedRunPolicy = 0
+	// MissedRunOnce executes the most recent missed run once, no matter how many runs were missed.
+	MissedRunOnce MissedRunPolicy = 1
+	// MissedRunAll executes every missed run in order, up to the MaxCatchUp option of the scheduler.
+	MissedRunAll MissedRunPolicy = 2
+)
+
+// Executor executes commands on a server. It is implemented by Client, ResilientClient and Pool.
+type Executor interface {
+	ExecuteResponse(ctx context.Context, command string) (*response.RCON, error)
+}
+
+// Job is a list of commands executed on a schedule.
+type Job struct {
+	// Name is the unique name of the job, which is used as the key of the saved state.
+	Name string
+	// Schedule decides when the job runs.
+	Schedule Schedule
+	// Commands is the commands executed in order on each executor of the scheduler.
+	Commands []string
+	// Jitter is the longest random delay added to each run, which spreads out jobs that are scheduled at
+	// the same time.
+	Jitter time.Duration
+	// MissedRuns is what happens to the runs that were missed.
+	MissedRuns MissedRunPolicy
+	// OnResult is called with the result of each run.
+	OnResult func(result JobResult)
+}
+
+// JobResult is the result of a single run of a job.
+type JobResult struct {
+	Job       string
+	Scheduled time.Time
+	Started   time.Time
+	Finished  time.Time
+	// Missed is true if the run was missed and executed late because of the missed run policy.
+	Missed   bool
+	Commands []CommandResult
+}
+
+// CommandResult is the result of a single command of a job on a single executor.
+type CommandResult struct {
+	// Executor is the index of the executor the command was executed on.
+	Executor int
+	Command  string
+	Response *response.RCON
+	Err      error
+}
+
+// Err returns the errors of all commands in the run joined together, or nil if every command succeeded.
+func (r JobResult) Err() error {
+	errs := make([]error, 0)
+
+	for _, command := range r.Commands {
+		if command.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", command.Command, command.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Scheduler executes commands on a schedule against one or more servers. Jobs may be added and removed
+// while the scheduler is running. It is safe to use from multiple goroutines at once.
+type Scheduler struct {
+	executors []Executor
+	opts      options.RCONScheduler
+	mu        sync.Mutex
+	jobs      map[string]*scheduledJob
+	lastRuns  map[string]time.Time
+	ctx       context.Context
+	wg        sync.WaitGroup
+}
+
+// scheduledJob is a job in the scheduler, with a nil cancel function while the scheduler is not running.
+type scheduledJob struct {
+	job    Job
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a scheduler that executes the commands of each job on all of the executors.
+func NewScheduler(executors []Executor, options ...options.RCONScheduler) *Scheduler {
+	return &Scheduler{
+		executors: executors,
+		opts:      parseSchedulerOptions(options...),
+		jobs:      make(map[string]*scheduledJob),
+		lastRuns:  make(map[string]time.Time),
+		ctx:       nil,
+	}
+}
+
+// Add adds the job to the scheduler, starting it right away if the scheduler is running.
+func (s *Scheduler) Add(job Job) error {
+	if len(job.Name) < 1 || job.Schedule == nil || len(job.Commands) < 1 {
+		return ErrInvalidJob
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.Name]; ok {
+		return ErrDuplicateJob
+	}
+
+	entry := &scheduledJob{
+		job:    job,
+		cancel: nil,
+	}
+
+	s.jobs[job.Name] = entry
+
+	// Jobs added while the scheduler is stopping are started by the next Run.
+	if s.ctx != nil && s.ctx.Err() == nil {
+		s.start(entry)
+	}
+
+	return nil
+}
+
+// Remove stops the job with the name and removes it from the scheduler, returning whether it was found. A
+// run that is already executing is cancelled.
+func (s *Scheduler) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.jobs[name]
+
+	if !ok {
+		return false
+	}
+
+	if entry.cancel != nil {
+		entry.cancel()
+	}
+
+	delete(s.jobs, name)
+
+	return true
+}
+
+// Run loads the saved state and runs the jobs until the context is cancelled, returning once all runs
+// that were executing have stopped. Runs that were missed since the saved state was written are handled
+// using the missed run policy of each job.
+func (s *Scheduler) Run(ctx context.Context) error {
+	lastRuns, err := s.loadState()
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+
+	if s.ctx != nil {
+		s.mu.Unlock()
+
+		return ErrSchedulerRunning
+	}
+
+	s.ctx = ctx
+	s.lastRuns = lastRuns
+
+	for _, entry := range s.jobs {
+		s.start(entry)
+	}
+
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	// Add no longer starts jobs once it sees the cancelled context while holding the lock, so no
+	// goroutines are added to the wait group while waiting for it.
+	s.mu.Lock()
+
+	for _, entry := range s.jobs {
+		entry.cancel = nil
+	}
+
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	s.mu.Lock()
+
+	s.ctx = nil
+
+	s.mu.Unlock()
+
+	return nil
+}
+
+// LastRun returns the scheduled time of the most recent run of the job with the name, or the zero time if
+// it has not run yet.
+func (s *Scheduler) LastRun(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastRuns[name]
+}
+
+// start starts the goroutine running the job, and must be called while holding the lock.
+func (s *Scheduler) start(entry *scheduledJob) {
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	entry.cancel = cancel
+
+	lastRun, ok := s.lastRuns[entry.job.Name]
+
+	if !ok {
+		lastRun = time.Now()
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		s.runJob(ctx, entry.job, lastRun)
+	}()
+}
+
+// runJob waits for each scheduled run of the job and executes it, until the context is cancelled or the
+// schedule has no more runs.
+func (s *Scheduler) runJob(ctx context.Context, job Job, cursor time.Time) {
+	for {
+		now := time.Now()
+		due := s.dueRuns(job, cursor, now)
+
+		for i, scheduled := range due {
+			if job.Jitter > 0 && !sleepContext(ctx, rand.N(job.Jitter)) {
+				return
+			}
+
+			s.execute(ctx, job, scheduled, i < len(due)-1 || now.Sub(scheduled) > missedRunTolerance)
+
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		cursor = now
+
+		next := job.Schedule.Next(cursor)
+
+		if next.IsZero() {
+			return
+		}
+
+		if !sleepContext(ctx, time.Until(next)) {
+			return
+		}
+	}
+}
+
+// dueRuns returns the runs of the job scheduled after the cursor and up to the current time that should
+// be executed, applying the missed run policy to the runs that are more than the tolerance late.
+func (s *Scheduler) dueRuns(job Job, cursor, now time.Time) []time.Time {
+	var (
+		missed []time.Time = nil
+		onTime time.Time
+	)
+
+	// Skipped runs are never executed, so the schedule is only walked from the earliest run that is still
+	// on time instead of from the cursor, which may be long ago after the program was not running.
+	if earliest := now.Add(-missedRunTolerance - time.Nanosecond); job.MissedRuns == MissedRunSkip && cursor.Before(earliest) {
+		cursor = earliest
+	}
+
+	for t := job.Schedule.Next(cursor); !t.IsZero() && !t.After(now); t = job.Schedule.Next(t) {
+		if now.Sub(t) <= missedRunTolerance {
+			onTime = t
+
+			continue
+		}
+
+		missed = append(missed, t)
+
+		if len(missed) > s.opts.MaxCatchUp {
+			missed = missed[1:]
+		}
+	}
+
+	result := make([]time.Time, 0)
+
+	switch job.MissedRuns {
+	case MissedRunOnce:
+		{
+			if len(missed) > 0 && onTime.IsZero() {
+				result = append(result, missed[len(missed)-1])
+			}
+		}
+	case MissedRunAll:
+		result = append(result, missed...)
+	}
+
+	if !onTime.IsZero() {
+		result = append(result, onTime)
+	}
+
+	return result
+}
+
+// execute runs the commands of the job on every executor at once, then saves the run and reports the
+// result.
+func (s *Scheduler) execute(ctx context.Context, job Job, scheduled time.Time, missed bool) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+
+	defer cancel()
+
+	result := JobResult{
+		Job:       job.Name,
+		Scheduled: scheduled,
+		Started:   time.Now(),
+		Finished:  time.Time{},
+		Missed:    missed,
+		Commands:  make([]CommandResult, len(s.executors)*len(job.Commands)),
+	}
+
+	wg := sync.WaitGroup{}
+
+	for i, executor := range s.executors {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j, command := range job.Commands {
+				resp, err := executor.ExecuteResponse(ctx, command)
+
+				result.Commands[i*len(job.Commands)+j] = CommandResult{
+					Executor: i,
+					Command:  command,
+					Response: resp,
+					Err:      err,
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	result.Finished = time.Now()
+
+	s.saveLastRun(job.Name, scheduled)
+
+	if job.OnResult != nil {
+		job.OnResult(result)
+	}
+}
+
+// saveLastRun records the run of the job and writes the state file if one is configured.
+func (s *Scheduler) saveLastRun(name string, scheduled time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A job that was removed while it was running is not saved, so it is not kept in the state file.
+	if _, ok := s.jobs[name]; !ok {
+		return
+	}
+
+	s.lastRuns[name] = scheduled
+
+	if len(s.opts.StateFile) < 1 {
+		return
+	}
+
+	if err := writeStateFile(s.opts.StateFile, s.lastRuns); err != nil && s.opts.OnError != nil {
+		s.opts.OnError(err)
+	}
+}
+
+// loadState reads the last runs from the state file, returning an empty state if the file does not exist.
+func (s *Scheduler) loadState() (map[string]time.Time, error) {
+	result := make(map[string]time.Time)
+
+	if len(s.opts.StateFile) < 1 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for name, t := range s.lastRuns {
+			result[name] = t
+		}
+
+		return result, nil
+	}
+
+	data, err := os.ReadFile(s.opts.StateFile)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("rcon: invalid scheduler state file: %w", err)
+	}
+
+	return result, nil
+}
+
+// writeStateFile writes the last runs to a temporary file and renames it over the state file, so the
+// state file is never left partially written.
+func writeStateFile(path string, lastRuns map[string]time.Time) error {
+	data, err := json.MarshalIndent(lastRuns, "", "\t")
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Countdown returns one-off jobs that each execute a command at an offset before the time, such as
+// warning players at 5 minutes, 1 minute and 10 seconds before a restart. The command function is called
+// with each offset to build the command, and the jobs are named after the name and the offset.
+func Countdown(name string, at time.Time, offsets []time.Duration, command func(remaining time.Duration) string) []Job {
+	result := make([]Job, 0, len(offsets))
+
+	for _, offset := range offsets {
+		result = append(result, Job{
+			Name:       fmt.Sprintf("%s/%s", name, offset),
+			Schedule:   At(at.Add(-offset)),
+			Commands:   []string{command(offset)},
+			Jitter:     0,
+			MissedRuns: MissedRunSkip,
+			OnResult:   nil,
+		})
+	}
+
+	return result
+}
+
+// sleepContext waits for the duration, returning false if the context was cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func parseSchedulerOptions(opts ...options.RCONScheduler) options.RCONScheduler {
+	if len(opts) < 1 {
+		return defaultSchedulerOptions
+	}
+
+	result := opts[0]
+
+	if result.Timeout <= 0 {
+		result.Timeout = defaultSchedulerOptions.Timeout
+	}
+
+	if result.MaxCatchUp < 1 {
+		result.MaxCatchUp = defaultSchedulerOptions.MaxCatchUp
+	}
+
+	return result
+}
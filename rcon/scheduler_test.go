@@ -0,0 +1,399 @@
+package rcon_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+func TestParseCron(t *testing.T) {
+	after := time.Date(2024, time.March, 15, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 4 * * *", time.Date(2024, time.March, 16, 4, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 6 * * mon-fri", time.Date(2024, time.March, 18, 6, 30, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2024, time.March, 22, 0, 0, 0, 0, time.UTC)},
+		{"5,35 10-12 * * *", time.Date(2024, time.March, 15, 10, 35, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		schedule, err := rcon.ParseCron(test.expression)
+
+		if err != nil {
+			t.Fatalf("%q: %v", test.expression, err)
+		}
+
+		if next := schedule.Next(after); !next.Equal(test.expected) {
+			t.Fatalf("%q: expected %s, received %s", test.expression, test.expected, next)
+		}
+	}
+
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := rcon.ParseCron(expression); err == nil {
+			t.Fatalf("%q: expected an error", expression)
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	after := time.Date(2024, time.March, 15, 10, 30, 20, 0, time.UTC)
+
+	if next := rcon.Every(time.Hour).Next(after); !next.Equal(time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next run: %s", next)
+	}
+
+	if next := rcon.Every(time.Minute * 5).Next(after); !next.Equal(time.Date(2024, time.March, 15, 10, 35, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next run: %s", next)
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	executors := []rcon.Executor{&testExecutor{}, &testExecutor{}}
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	scheduler := rcon.NewScheduler(executors, options.RCONScheduler{
+		StateFile: stateFile,
+	})
+
+	restartAt := time.Now().Add(time.Millisecond * 300)
+	results := make(chan rcon.JobResult, 3)
+
+	jobs := rcon.Countdown("restart", restartAt, []time.Duration{time.Millisecond * 200, time.Millisecond * 100}, func(remaining time.Duration) string {
+		return fmt.Sprintf("say Restart in %s", remaining)
+	})
+
+	for _, job := range jobs {
+		job.OnResult = func(result rcon.JobResult) {
+			results <- result
+		}
+
+		if err := scheduler.Add(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := scheduler.Add(jobs[0]); err != rcon.ErrDuplicateJob {
+		t.Fatalf("expected ErrDuplicateJob, received %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- scheduler.Run(ctx)
+	}()
+
+	for _, expected := range []string{"say Restart in 200ms", "say Restart in 100ms"} {
+		select {
+		case result := <-results:
+			{
+				if err := result.Err(); err != nil {
+					t.Fatal(err)
+				}
+
+				if result.Missed || len(result.Commands) != 2 || result.Commands[0].Command != expected || result.Commands[1].Executor != 1 {
+					t.Fatalf("unexpected result: %+v", result)
+				}
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out waiting for the job to run")
+		}
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, executor := range executors {
+		if commands := executor.(*testExecutor).Commands(); len(commands) != 2 {
+			t.Fatalf("expected 2 commands, received %v", commands)
+		}
+	}
+
+	data, err := os.ReadFile(stateFile)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := make(map[string]time.Time)
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(state) != 2 || !state[jobs[1].Name].Equal(restartAt.Add(-time.Millisecond*100)) {
+		t.Fatalf("unexpected state: %v", state)
+	}
+}
+
+func TestSchedulerMissedRuns(t *testing.T) {
+	now := time.Now()
+	schedule := rcon.At(now.Add(-time.Hour*3), now.Add(-time.Hour*2), now.Add(-time.Hour))
+
+	tests := []struct {
+		policy   rcon.MissedRunPolicy
+		expected []time.Time
+	}{
+		{rcon.MissedRunSkip, []time.Time{}},
+		{rcon.MissedRunOnce, []time.Time{now.Add(-time.Hour)}},
+		{rcon.MissedRunAll, []time.Time{now.Add(-time.Hour * 3), now.Add(-time.Hour * 2), now.Add(-time.Hour)}},
+	}
+
+	for _, test := range tests {
+		stateFile := filepath.Join(t.TempDir(), "state.json")
+
+		data, err := json.Marshal(map[string]time.Time{"save": now.Add(-time.Hour * 4)})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(stateFile, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		scheduler := rcon.NewScheduler([]rcon.Executor{&testExecutor{}}, options.RCONScheduler{
+			StateFile: stateFile,
+		})
+
+		mu := sync.Mutex{}
+		scheduled := make([]time.Time, 0)
+
+		err = scheduler.Add(rcon.Job{
+			Name:       "save",
+			Schedule:   schedule,
+			Commands:   []string{"save-all"},
+			MissedRuns: test.policy,
+			OnResult: func(result rcon.JobResult) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if !result.Missed {
+					t.Errorf("expected the run to be missed: %+v", result)
+				}
+
+				scheduled = append(scheduled, result.Scheduled)
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+
+		if err := scheduler.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		cancel()
+
+		mu.Lock()
+
+		if len(scheduled) != len(test.expected) {
+			t.Fatalf("policy %d: expected %d runs, received %d", test.policy, len(test.expected), len(scheduled))
+		}
+
+		for i, expected := range test.expected {
+			if !scheduled[i].Equal(expected) {
+				t.Fatalf("policy %d: expected run at %s, received %s", test.policy, expected, scheduled[i])
+			}
+		}
+
+		mu.Unlock()
+	}
+}
+
+func TestSchedulerAddWhileStopping(t *testing.T) {
+	scheduler := rcon.NewScheduler([]rcon.Executor{&testExecutor{}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go (func() { done <- scheduler.Run(ctx) })()
+
+	time.Sleep(time.Millisecond * 20)
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+
+		go (func(i int) {
+			defer wg.Done()
+
+			if i == 32 {
+				cancel()
+			}
+
+			if err := scheduler.Add(rcon.Job{Name: fmt.Sprintf("job-%d", i), Schedule: rcon.Every(time.Hour), Commands: []string{"list"}}); err != nil {
+				t.Error(err)
+			}
+		})(i)
+	}
+
+	wg.Wait()
+
+	select {
+	case err := <-done:
+		{
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected the scheduler to stop")
+	}
+}
+
+func TestSchedulerSkipLongDowntime(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	data, err := json.Marshal(map[string]time.Time{"tick": time.Now().Add(-time.Hour * 24)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(stateFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	schedule := &countingSchedule{Schedule: rcon.Every(time.Second)}
+
+	scheduler := rcon.NewScheduler([]rcon.Executor{&testExecutor{}}, options.RCONScheduler{
+		StateFile: stateFile,
+	})
+
+	if err := scheduler.Add(rcon.Job{Name: "tick", Schedule: schedule, Commands: []string{"list"}, MissedRuns: rcon.MissedRunSkip}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+
+	defer cancel()
+
+	if err := scheduler.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the runs within the missed run tolerance are walked, instead of every second of the downtime.
+	if calls := schedule.calls.Load(); calls > 200 {
+		t.Fatalf("expected the skipped runs to not be walked, received %d calls", calls)
+	}
+}
+
+func TestSchedulerRemoveWhileRunning(t *testing.T) {
+	started := make(chan struct{})
+
+	scheduler := rcon.NewScheduler([]rcon.Executor{executorFunc(func(ctx context.Context, command string) (*response.RCON, error) {
+		close(started)
+
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	})})
+
+	done := make(chan struct{})
+
+	err := scheduler.Add(rcon.Job{
+		Name:     "save",
+		Schedule: rcon.At(time.Now().Add(time.Millisecond * 20)),
+		Commands: []string{"save-all"},
+		OnResult: func(result rcon.JobResult) { close(done) },
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	go scheduler.Run(ctx)
+
+	<-started
+
+	scheduler.Remove("save")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected the removed job to stop")
+	}
+
+	if lastRun := scheduler.LastRun("save"); !lastRun.IsZero() {
+		t.Fatalf("expected the removed job to not be saved, received %s", lastRun)
+	}
+}
+
+// countingSchedule counts the calls to the Next method of the schedule.
+type countingSchedule struct {
+	rcon.Schedule
+	calls atomic.Int32
+}
+
+func (s *countingSchedule) Next(after time.Time) time.Time {
+	s.calls.Add(1)
+
+	return s.Schedule.Next(after)
+}
+
+// executorFunc executes commands using the function itself.
+type executorFunc func(ctx context.Context, command string) (*response.RCON, error)
+
+func (f executorFunc) ExecuteResponse(ctx context.Context, command string) (*response.RCON, error) {
+	return f(ctx, command)
+}
+
+// testExecutor records the commands it executes and responds with the command itself.
+type testExecutor struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func (e *testExecutor) ExecuteResponse(ctx context.Context, command string) (*response.RCON, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.commands = append(e.commands, command)
+
+	return &response.RCON{
+		Output:    command,
+		Fragments: 1,
+	}, nil
+}
+
+func (e *testExecutor) Commands() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]string{}, e.commands...)
+}
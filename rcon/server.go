@@ -0,0 +1,297 @@
+package rcon
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+)
+
+var (
+	// ErrServerClosed is returned by the Serve() and ListenAndServe() methods of a server after it has been closed.
+	ErrServerClosed = errors.New("rcon: server closed")
+)
+
+var (
+	defaultServerOptions = options.RCONServer{
+		FragmentSize:     4096,
+		MaxConnections:   0,
+		MaxLoginAttempts: 3,
+		IdleTimeout:      0,
+		MaxCommandLength: MaxPayloadLength,
+	}
+)
+
+// Handler handles the commands received by an RCON server. The returned output is sent back to the client,
+// and the context is cancelled when the server is closed. Commands of the same connection are handled one
+// at a time, but commands of different connections may be handled at the same time.
+type Handler interface {
+	HandleCommand(ctx context.Context, command string) string
+}
+
+// HandlerFunc is a function that can be used as a Handler.
+type HandlerFunc func(ctx context.Context, command string) string
+
+// HandleCommand calls the function.
+func (f HandlerFunc) HandleCommand(ctx context.Context, command string) string {
+	return f(ctx, command)
+}
+
+// Server is a server that speaks the server side of the RCON protocol, passing the commands of logged in
+// clients to a handler.
+type Server struct {
+	password string
+	handler  Handler
+	opts     options.RCONServer
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	closed   bool
+	mutex    sync.Mutex
+}
+
+// NewServer creates a new RCON server that accepts logins using the password and passes commands to the
+// handler.
+func NewServer(password string, handler Handler, options ...options.RCONServer) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		password: password,
+		handler:  handler,
+		opts:     parseServerOptions(options...),
+		listener: nil,
+		conns:    make(map[net.Conn]struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+		closed:   false,
+	}
+}
+
+// ListenAndServe listens for TCP connections on the address and serves each of them.
+func (s *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on the listener and serves each of them in a separate goroutine until the
+// server is closed. Serve always closes the listener, and returns ErrServerClosed if the server was closed
+// using Close().
+func (s *Server) Serve(listener net.Listener) error {
+	s.mutex.Lock()
+
+	if s.closed {
+		s.mutex.Unlock()
+
+		listener.Close()
+
+		return ErrServerClosed
+	}
+
+	s.listener = listener
+
+	s.mutex.Unlock()
+
+	defer listener.Close()
+
+	for {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			s.mutex.Lock()
+			closed := s.closed
+			s.mutex.Unlock()
+
+			if closed {
+				return ErrServerClosed
+			}
+
+			return err
+		}
+
+		if !s.trackConn(conn) {
+			conn.Close()
+
+			continue
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops the server, closing the listener and all open connections.
+func (s *Server) Close() error {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.closed = true
+
+	s.cancel()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Close()
+}
+
+// trackConn adds the connection to the open connections, returning false if the server is closed or the
+// maximum amount of connections is reached.
+func (s *Server) trackConn(conn net.Conn) bool {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	if s.closed || (s.opts.MaxConnections > 0 && len(s.conns) >= s.opts.MaxConnections) {
+		return false
+	}
+
+	s.conns[conn] = struct{}{}
+
+	return true
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer (func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+
+		conn.Close()
+	})()
+
+	var (
+		authenticated bool = false
+		failedLogins  int  = 0
+	)
+
+	for {
+		if s.opts.IdleTimeout > 0 {
+			if err := conn.SetReadDeadline(time.Now().Add(s.opts.IdleTimeout)); err != nil {
+				return
+			}
+		}
+
+		// Malformed packets leave the stream in an unknown state, so the connection is closed.
+		packet, err := ReadPacket(conn)
+
+		if err != nil {
+			return
+		}
+
+		switch packet.Type {
+		case PacketTypeLogin:
+			{
+				// Login response packet
+				// https://wiki.vg/RCON#3:_Login
+				if subtle.ConstantTimeCompare([]byte(packet.Payload), []byte(s.password)) == 1 {
+					authenticated = true
+
+					if err := WritePacket(conn, Packet{RequestID: packet.RequestID, Type: PacketTypeAuthResponse, Payload: ""}); err != nil {
+						return
+					}
+
+					continue
+				}
+
+				authenticated = false
+				failedLogins++
+
+				if err := WritePacket(conn, Packet{RequestID: -1, Type: PacketTypeAuthResponse, Payload: ""}); err != nil {
+					return
+				}
+
+				if failedLogins >= s.opts.MaxLoginAttempts {
+					return
+				}
+			}
+		case PacketTypeCommand:
+			{
+				// Commands sent before logging in are answered with a failed login response, the same
+				// way as the vanilla server does.
+				if !authenticated {
+					if err := WritePacket(conn, Packet{RequestID: -1, Type: PacketTypeAuthResponse, Payload: ""}); err != nil {
+						return
+					}
+
+					continue
+				}
+
+				if len(packet.Payload) > s.opts.MaxCommandLength {
+					return
+				}
+
+				output := s.handler.HandleCommand(s.ctx, packet.Payload)
+
+				if err := s.writeResponse(conn, packet.RequestID, output); err != nil {
+					return
+				}
+			}
+		default:
+			{
+				// Unknown request types are answered with a command response, which clients use to
+				// find the end of fragmented responses.
+				if err := s.writeResponse(conn, packet.RequestID, fmt.Sprintf("Unknown request %x", packet.Type)); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeResponse writes the output as one or more command response packets.
+// https://wiki.vg/RCON#Fragmentation
+func (s *Server) writeResponse(conn net.Conn, requestID int32, output string) error {
+	for {
+		length := min(len(output), s.opts.FragmentSize)
+
+		// Command response packet
+		// https://wiki.vg/RCON#0:_Command_response
+		if err := WritePacket(conn, Packet{RequestID: requestID, Type: PacketTypeResponse, Payload: output[:length]}); err != nil {
+			return err
+		}
+
+		output = output[length:]
+
+		if len(output) < 1 {
+			return nil
+		}
+	}
+}
+
+func parseServerOptions(opts ...options.RCONServer) options.RCONServer {
+	if len(opts) < 1 {
+		return defaultServerOptions
+	}
+
+	result := opts[0]
+
+	if result.FragmentSize < 1 || result.FragmentSize > MaxPayloadLength {
+		result.FragmentSize = defaultServerOptions.FragmentSize
+	}
+
+	if result.MaxLoginAttempts < 1 {
+		result.MaxLoginAttempts = defaultServerOptions.MaxLoginAttempts
+	}
+
+	if result.MaxCommandLength < 1 {
+		result.MaxCommandLength = defaultServerOptions.MaxCommandLength
+	}
+
+	return result
+}
@@ -0,0 +1,186 @@
+package rcon_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/rcon"
+)
+
+func TestServer(t *testing.T) {
+	output := strings.Repeat("abcdefgh", 1500)
+
+	addr := startServer(t, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		if command == "help" {
+			return output
+		}
+
+		return "Unknown or incomplete command: " + command
+	}))
+
+	client, err := rcon.Dial(addr.IP.String(), uint16(addr.Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	if err := client.Login(testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.ExecuteResponse(context.Background(), "help")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Output != output || resp.Fragments != 3 {
+		t.Fatalf("expected 3 fragments of %d bytes, received %d fragments of %d bytes", len(output), resp.Fragments, len(resp.Output))
+	}
+
+	result, err := client.Execute(context.Background(), "foo")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != "Unknown or incomplete command: foo" {
+		t.Fatalf("unexpected response: %q", result)
+	}
+}
+
+func TestServerInvalidPassword(t *testing.T) {
+	addr := startServer(t, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		t.Error("expected the command to not be handled")
+
+		return ""
+	}))
+
+	client, err := rcon.Dial(addr.IP.String(), uint16(addr.Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	if err := client.Login("wrong"); !errors.Is(err, rcon.ErrInvalidPassword) {
+		t.Fatalf("expected an invalid password error, received %v", err)
+	}
+}
+
+func TestServerUnauthenticatedCommand(t *testing.T) {
+	addr := startServer(t, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		t.Error("expected the command to not be handled")
+
+		return ""
+	}))
+
+	conn, err := net.Dial("tcp", addr.String())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	if err := rcon.WritePacket(conn, rcon.Packet{RequestID: 5, Type: rcon.PacketTypeCommand, Payload: "stop"}); err != nil {
+		t.Fatal(err)
+	}
+
+	packet, err := rcon.ReadPacket(conn)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if packet.RequestID != -1 || packet.Type != rcon.PacketTypeAuthResponse {
+		t.Fatalf("expected a failed login response, received %+v", packet)
+	}
+}
+
+func TestServerMaxConnections(t *testing.T) {
+	addr := startServer(t, rcon.HandlerFunc(func(ctx context.Context, command string) string {
+		return command
+	}), options.RCONServer{MaxConnections: 1})
+
+	first, err := rcon.Dial(addr.IP.String(), uint16(addr.Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer first.Close()
+
+	if err := first.Login(testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := rcon.Dial(addr.IP.String(), uint16(addr.Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer second.Close()
+
+	if err := second.Login(testPassword); err == nil {
+		t.Fatal("expected the second connection to be closed by the server")
+	}
+}
+
+// testDropConnection is returned by a test handler to close the connection instead of responding.
+const testDropConnection = "\x00drop"
+
+// startServer starts an RCON server on localhost with the handler.
+func startServer(t *testing.T, handler rcon.Handler, opts ...options.RCONServer) *net.TCPAddr {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := rcon.NewServer(testPassword, handler, opts...)
+
+	go server.Serve(&dropListener{listener})
+
+	t.Cleanup(func() { server.Close() })
+
+	return listener.Addr().(*net.TCPAddr)
+}
+
+// dropListener accepts connections that are closed instead of sending a response of testDropConnection.
+type dropListener struct {
+	net.Listener
+}
+
+func (l *dropListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &dropConn{conn}, nil
+}
+
+type dropConn struct {
+	net.Conn
+}
+
+func (c *dropConn) Write(data []byte) (int, error) {
+	if bytes.Contains(data, []byte(testDropConnection)) {
+		c.Conn.Close()
+
+		return 0, net.ErrClosed
+	}
+
+	return c.Conn.Write(data)
+}
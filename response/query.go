@@ -11,10 +11,56 @@ type QueryBasic struct {
 	MaxPlayers    uint64            `json:"max_players"`
 	HostPort      uint16            `json:"host_port"`
 	HostIP        string            `json:"host_ip"`
+	ReplyAddress  string            `json:"reply_address"`
 }
 
-// QueryFull is the response data returned from doing a full query on a server.
+// QueryFull is the response data returned from doing a full query on a server. The typed fields are
+// parsed from the key/value data, and any keys without a typed field are copied into Extra.
 type QueryFull struct {
-	Data    map[string]string `json:"data"`
-	Players []string          `json:"players"`
+	Edition       QueryEdition      `json:"edition"`
+	MOTD          formatting.Result `json:"motd"`
+	GameType      string            `json:"game_type"`
+	GameID        string            `json:"game_id"`
+	Version       string            `json:"version"`
+	Plugins       QueryPlugins      `json:"plugins"`
+	Map           string            `json:"map"`
+	OnlinePlayers uint64            `json:"online_players"`
+	MaxPlayers    uint64            `json:"max_players"`
+	HostPort      uint16            `json:"host_port"`
+	HostIP        string            `json:"host_ip"`
+	ServerEngine  string            `json:"server_engine"`
+	Whitelist     *bool             `json:"whitelist"`
+	Extra         map[string]string `json:"extra"`
+	Data          map[string]string `json:"data"`
+	Players       []string          `json:"players"`
+	ReplyAddress  string            `json:"reply_address"`
+}
+
+// QueryEdition is the edition of the Minecraft server that responded to a query.
+type QueryEdition string
+
+var (
+	// QueryEditionJava is a Java Edition server, which reports the "MINECRAFT" game ID.
+	QueryEditionJava QueryEdition = "java"
+	// QueryEditionBedrock is a Bedrock Edition server such as PocketMine-MP, Nukkit or Geyser, which reports
+	// the "MINECRAFTPE" game ID.
+	QueryEditionBedrock QueryEdition = "bedrock"
+)
+
+// QueryPlugins is the server software and plugin list parsed from the "plugins" value of a full query,
+// which Bukkit-based servers report as "<software> on <platform> <version>: <plugin> <version>; ...".
+// Hidden is true when the server reports its software but hides the plugin list.
+type QueryPlugins struct {
+	Raw             string        `json:"raw"`
+	Software        string        `json:"software"`
+	SoftwareVersion string        `json:"software_version"`
+	Platform        string        `json:"platform"`
+	Plugins         []QueryPlugin `json:"list"`
+	Hidden          bool          `json:"hidden"`
+}
+
+// QueryPlugin is a single plugin reported by the server.
+type QueryPlugin struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
 }
@@ -0,0 +1,7 @@
+package response
+
+// RCON is the response data returned from executing a command over RCON.
+type RCON struct {
+	Output    string `json:"output"`
+	Fragments int    `json:"fragments"`
+}
@@ -66,7 +66,7 @@ type LegacyPlayers struct {
 // StatusBedrock is the response data returned from a Minecraft Bedrock Edition server.
 type StatusBedrock struct {
 	ServerGUID      int64              `json:"server_guid"`
-	Edition         *string            `json:"edition"`
+	Edition         *BedrockEdition    `json:"edition"`
 	MOTD            *formatting.Result `json:"motd"`
 	ProtocolVersion *int64             `json:"protocol_version"`
 	Version         *string            `json:"version"`
@@ -77,4 +77,62 @@ type StatusBedrock struct {
 	GamemodeID      *int64             `json:"gamemode_id"`
 	PortIPv4        *uint16            `json:"port_ipv4"`
 	PortIPv6        *uint16            `json:"port_ipv6"`
+	Latency         time.Duration      `json:"-"`
+	PingStatistics  PingStatistics     `json:"ping_statistics"`
+	ReplyAddress    string             `json:"reply_address"`
+	Software        BedrockSoftware    `json:"software"`
+}
+
+// BedrockEdition is the edition reported by a Minecraft Bedrock Edition server. Values other than the
+// known editions are kept as they were reported.
+type BedrockEdition string
+
+var (
+	// BedrockEditionPocket is the edition reported by regular Bedrock Edition servers (MCPE).
+	BedrockEditionPocket BedrockEdition = "MCPE"
+	// BedrockEditionEducation is the edition reported by Minecraft Education servers (MCEE).
+	BedrockEditionEducation BedrockEdition = "MCEE"
+)
+
+// Known returns whether the edition is one of the known Bedrock editions.
+func (e BedrockEdition) Known() bool {
+	return e == BedrockEditionPocket || e == BedrockEditionEducation
+}
+
+// BedrockSoftwareName is the name of a server software that can answer the Bedrock Edition status ping.
+type BedrockSoftwareName string
+
+var (
+	// BedrockSoftwareUnknown is used when the server software could not be detected.
+	BedrockSoftwareUnknown BedrockSoftwareName = "unknown"
+	// BedrockSoftwareDedicated is the official Bedrock Dedicated Server (BDS).
+	BedrockSoftwareDedicated BedrockSoftwareName = "bds"
+	// BedrockSoftwareEndstone is the Endstone plugin platform running on top of BDS.
+	BedrockSoftwareEndstone BedrockSoftwareName = "endstone"
+	// BedrockSoftwareGeyser is the Geyser bridge in front of a Java Edition server.
+	BedrockSoftwareGeyser BedrockSoftwareName = "geyser"
+	// BedrockSoftwareNukkit is the Nukkit server software and its forks.
+	BedrockSoftwareNukkit BedrockSoftwareName = "nukkit"
+	// BedrockSoftwarePocketMine is the PocketMine-MP server software.
+	BedrockSoftwarePocketMine BedrockSoftwareName = "pocketmine-mp"
+)
+
+// BedrockSoftware is the server software detected from the status of a Bedrock Edition server. Detection is
+// based on default values and field patterns of each software, so the confidence is a value between 0 and 1
+// of how likely the detection is to be correct.
+type BedrockSoftware struct {
+	Name       BedrockSoftwareName `json:"name"`
+	Confidence float64             `json:"confidence"`
+}
+
+// PingStatistics is the round-trip latency measured over one or more pings sent to a server. Retransmissions
+// of an unanswered ping are not counted as separate pings.
+type PingStatistics struct {
+	Sent     int           `json:"sent"`
+	Received int           `json:"received"`
+	Lost     int           `json:"lost"`
+	Min      time.Duration `json:"min"`
+	Avg      time.Duration `json:"avg"`
+	Max      time.Duration `json:"max"`
+	Jitter   time.Duration `json:"jitter"`
 }
@@ -1,19 +1,21 @@
 package status
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/binary"
-	"fmt"
+	"errors"
 	"io"
 	"math/rand"
 	"net"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/internal/udp"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/response"
 )
@@ -22,20 +24,29 @@ var (
 	defaultBedrockStatusOptions = options.StatusBedrock{
 		Timeout:    time.Second * 5,
 		ClientGUID: 0,
+		Retry: options.Retry{
+			Attempts: 3,
+		},
 	}
 	bedrockMagic = []byte{0x00, 0xFF, 0xFF, 0x00, 0xFE, 0xFE, 0xFE, 0xFE, 0xFD, 0xFD, 0xFD, 0xFD, 0x12, 0x34, 0x56, 0x78}
 )
 
+type bedrockUnconnectedPong struct {
+	Timestamp  int64
+	ServerGUID int64
+	ServerID   string
+}
+
 // Bedrock retrieves the status of a Bedrock Edition Minecraft server.
 func Bedrock(ctx context.Context, hostname string, port uint16, options ...options.StatusBedrock) (*response.StatusBedrock, error) {
 	r := make(chan *response.StatusBedrock, 1)
 	e := make(chan error, 1)
 
 	go func() {
-		result, err := getStatusBedrock(hostname, port, options...)
+		result, err := getStatusBedrock(ctx, hostname, port, options...)
 
 		if err != nil {
-			e <- err
+			e <- mcerrors.Classify(err)
 		} else if result != nil {
 			r <- result
 		}
@@ -44,10 +55,10 @@ func Bedrock(ctx context.Context, hostname string, port uint16, options ...optio
 	select {
 	case <-ctx.Done():
 		if v := ctx.Err(); v != nil {
-			return nil, v
+			return nil, mcerrors.Classify(v)
 		}
 
-		return nil, context.DeadlineExceeded
+		return nil, mcerrors.Classify(context.DeadlineExceeded)
 	case v := <-r:
 		return v, nil
 	case v := <-e:
@@ -55,10 +66,10 @@ func Bedrock(ctx context.Context, hostname string, port uint16, options ...optio
 	}
 }
 
-func getStatusBedrock(hostname string, port uint16, options ...options.StatusBedrock) (*response.StatusBedrock, error) {
+func getStatusBedrock(ctx context.Context, hostname string, port uint16, options ...options.StatusBedrock) (*response.StatusBedrock, error) {
 	opts := parseBedrockStatusOptions(options...)
 
-	conn, err := net.DialTimeout("udp", fmt.Sprintf("%s:%d", hostname, port), opts.Timeout)
+	conn, err := udp.Dial(hostname, port, opts.Timeout, opts.UnconnectedSocket)
 
 	if err != nil {
 		return nil, err
@@ -66,106 +77,250 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 
 	defer conn.Close()
 
-	r := bufio.NewReader(conn)
+	deadline := time.Now().Add(opts.Timeout)
 
-	if err = conn.SetDeadline(time.Now().Add(opts.Timeout)); err != nil {
+	if err = conn.SetDeadline(deadline); err != nil {
 		return nil, err
 	}
 
-	// Unconnected ping packet
-	// https://wiki.vg/Raknet_Protocol#Unconnected_Ping
+	var (
+		pingCount     int                     = max(opts.PingCount, 1)
+		pingTimeout   time.Duration           = opts.PingTimeout
+		pingsSent     int                     = 0
+		latencies     []time.Duration         = make([]time.Duration, 0, pingCount)
+		lastTimestamp int64                   = 0
+		pong          *bedrockUnconnectedPong = nil
+		replyAddr     net.Addr                = nil
+		lastErr       error                   = nil
+	)
+
+	if pingTimeout <= 0 {
+		pingTimeout = opts.Timeout / time.Duration(pingCount)
+	}
+
+	for i := 0; i < pingCount && time.Now().Before(deadline); i++ {
+		sentAt := make(map[int64]time.Time)
+
+		err := udp.Exchange(
+			ctx,
+			conn,
+			minTime(time.Now().Add(pingTimeout), deadline),
+			opts.Retry,
+			func(attempt int) ([]byte, error) {
+				// The timestamp is echoed back by the server and is used to match each pong to its ping, so it
+				// must be unique even when multiple pings are sent within the same millisecond.
+				timestamp := max(time.Now().UnixMilli(), lastTimestamp+1)
+				lastTimestamp = timestamp
+
+				buf := &bytes.Buffer{}
+
+				// Unconnected ping packet
+				// https://wiki.vg/Raknet_Protocol#Unconnected_Ping
+				if err := writeBedrockUnconnectedPing(buf, timestamp, opts.ClientGUID); err != nil {
+					return nil, err
+				}
+
+				sentAt[timestamp] = time.Now()
+
+				// Retransmissions of an unanswered ping are counted as the same ping.
+				if attempt == 0 {
+					pingsSent++
+				}
+
+				return buf.Bytes(), nil
+			},
+			func(data []byte, addr net.Addr) (bool, error) {
+				// Other datagrams may be received when using an unconnected socket, so anything that
+				// is not an unconnected pong is ignored.
+				if !isBedrockUnconnectedPong(data) {
+					return false, nil
+				}
+
+				// Unconnected pong packet
+				// https://wiki.vg/Raknet_Protocol#Unconnected_Pong
+				result, err := readBedrockUnconnectedPong(bytes.NewReader(data))
+
+				// A malformed pong is ignored like any other stray datagram, so that it does not fail the
+				// remaining pings.
+				if err != nil {
+					return false, nil
+				}
+
+				// A pong with an unknown timestamp is a late reply to a ping that was already
+				// counted as lost, so it is ignored.
+				start, ok := sentAt[result.Timestamp]
+
+				if !ok {
+					return false, nil
+				}
+
+				latencies = append(latencies, time.Since(start))
+				pong = result
+				replyAddr = addr
+
+				return true, nil
+			},
+		)
+
+		if err != nil {
+			if !errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil, err
+			}
+
+			lastErr = err
+		}
+	}
+
+	if pong == nil {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+
+		return nil, os.ErrDeadlineExceeded
+	}
+
+	return formatBedrockStatusResponse(pong, replyAddr, calculatePingStatistics(pingsSent, latencies))
+}
+
+// isBedrockUnconnectedPong reports whether the datagram has the packet ID and magic of an unconnected pong.
+func isBedrockUnconnectedPong(data []byte) bool {
+	return len(data) >= 33 && data[0] == 0x1C && bytes.Equal(data[17:33], bedrockMagic)
+}
+
+func writeBedrockUnconnectedPing(w io.Writer, timestamp int64, clientGUID int64) error {
+	buf := &bytes.Buffer{}
+
+	// Packet ID - byte
+	if err := buf.WriteByte(0x01); err != nil {
+		return err
+	}
+
+	// Time - int64
+	if err := binary.Write(buf, binary.BigEndian, timestamp); err != nil {
+		return err
+	}
+
+	// Magic - bytes
+	if _, err := buf.Write(bedrockMagic); err != nil {
+		return err
+	}
+
+	// Client GUID - int64
+	if err := binary.Write(buf, binary.BigEndian, clientGUID); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(w, buf)
+
+	return err
+}
+
+func readBedrockUnconnectedPong(r io.Reader) (*bedrockUnconnectedPong, error) {
+	var result bedrockUnconnectedPong
+
+	// Type - byte
 	{
-		buf := &bytes.Buffer{}
+		var packetType byte
 
-		// Packet ID - byte
-		if err := buf.WriteByte(0x01); err != nil {
+		if err := binary.Read(r, binary.BigEndian, &packetType); err != nil {
 			return nil, err
 		}
 
-		// Time - int64
-		if err := binary.Write(buf, binary.BigEndian, time.Now().UnixMilli()); err != nil {
-			return nil, err
+		if packetType != 0x1C {
+			return nil, mcerrors.NewUnexpectedPacket("statusbedrock", 0x1C, int64(packetType))
 		}
+	}
 
-		// Magic - bytes
-		if _, err := buf.Write(bedrockMagic); err != nil {
+	// Time - int64
+	{
+		if err := binary.Read(r, binary.BigEndian, &result.Timestamp); err != nil {
 			return nil, err
 		}
+	}
 
-		// Client GUID - int64
-		if err := binary.Write(buf, binary.BigEndian, opts.ClientGUID); err != nil {
+	// Server GUID - int64
+	{
+		if err := binary.Read(r, binary.BigEndian, &result.ServerGUID); err != nil {
 			return nil, err
 		}
+	}
 
-		if _, err := io.Copy(conn, buf); err != nil {
+	// Magic - bytes
+	{
+		data := make([]byte, 16)
+
+		if _, err := io.ReadFull(r, data); err != nil {
 			return nil, err
 		}
 	}
 
-	var serverGUID int64
-	var serverID string
-
-	// Unconnected pong packet
-	// https://wiki.vg/Raknet_Protocol#Unconnected_Pong
+	// Server ID - string
 	{
-		// Type - byte
-		{
-			var packetType byte
-
-			if err := binary.Read(r, binary.BigEndian, &packetType); err != nil {
-				return nil, err
-			}
+		var length uint16
 
-			if packetType != 0x1C {
-				return nil, fmt.Errorf("statusbedrock: received unexpected packet type (expected=0x1C, received=0x%02X)", packetType)
-			}
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return nil, err
 		}
 
-		// Time - int64
-		{
-			var time int64
+		data := make([]byte, length)
 
-			if err := binary.Read(r, binary.BigEndian, &time); err != nil {
-				return nil, err
-			}
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
 		}
 
-		// Server GUID - int64
-		{
-			if err := binary.Read(r, binary.BigEndian, &serverGUID); err != nil {
-				return nil, err
-			}
-		}
+		result.ServerID = string(data)
+	}
 
-		// Magic - bytes
-		{
-			data := make([]byte, 16)
+	return &result, nil
+}
 
-			if _, err := r.Read(data); err != nil {
-				return nil, err
-			}
-		}
+func calculatePingStatistics(sent int, latencies []time.Duration) response.PingStatistics {
+	result := response.PingStatistics{
+		Sent:     sent,
+		Received: len(latencies),
+		Lost:     sent - len(latencies),
+	}
 
-		// Server ID - string
-		{
-			var length uint16
+	if len(latencies) < 1 {
+		return result
+	}
 
-			if err := binary.Read(r, binary.BigEndian, &length); err != nil {
-				return nil, err
-			}
+	var total, totalDeviation time.Duration
 
-			data := make([]byte, length)
+	result.Min = latencies[0]
+	result.Max = latencies[0]
 
-			if _, err = r.Read(data); err != nil {
-				return nil, err
+	for i, latency := range latencies {
+		total += latency
+
+		result.Min = min(result.Min, latency)
+		result.Max = max(result.Max, latency)
+
+		// Jitter is the mean deviation between consecutive round-trip times.
+		// https://datatracker.ietf.org/doc/html/rfc3550#section-6.4.1
+		if i > 0 {
+			deviation := latency - latencies[i-1]
+
+			if deviation < 0 {
+				deviation = -deviation
 			}
 
-			serverID = string(data)
+			totalDeviation += deviation
 		}
 	}
 
-	response := response.StatusBedrock{
-		ServerGUID:      serverGUID,
+	result.Avg = total / time.Duration(len(latencies))
+
+	if len(latencies) > 1 {
+		result.Jitter = totalDeviation / time.Duration(len(latencies)-1)
+	}
+
+	return result
+}
+
+func formatBedrockStatusResponse(pong *bedrockUnconnectedPong, replyAddr net.Addr, pingStatistics response.PingStatistics) (*response.StatusBedrock, error) {
+	result := response.StatusBedrock{
+		ServerGUID:      pong.ServerGUID,
 		Edition:         nil,
 		MOTD:            nil,
 		ProtocolVersion: nil,
@@ -177,9 +332,13 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 		GamemodeID:      nil,
 		PortIPv4:        nil,
 		PortIPv6:        nil,
+		Latency:         pingStatistics.Avg,
+		PingStatistics:  pingStatistics,
+		ReplyAddress:    replyAddr.String(),
+		Software:        response.BedrockSoftware{},
 	}
 
-	splitID := strings.Split(serverID, ";")
+	splitID := strings.Split(pong.ServerID, ";")
 
 	var motd string
 
@@ -191,7 +350,7 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 		switch k {
 		case 0:
 			{
-				response.Edition = pointerOf(value)
+				result.Edition = pointerOf(response.BedrockEdition(value))
 
 				break
 			}
@@ -209,13 +368,13 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 					return nil, err
 				}
 
-				response.ProtocolVersion = &protocolVersion
+				result.ProtocolVersion = &protocolVersion
 
 				break
 			}
 		case 3:
 			{
-				response.Version = pointerOf(value)
+				result.Version = pointerOf(value)
 
 				break
 			}
@@ -227,7 +386,7 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 					return nil, err
 				}
 
-				response.OnlinePlayers = &onlinePlayers
+				result.OnlinePlayers = &onlinePlayers
 
 				break
 			}
@@ -239,13 +398,13 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 					return nil, err
 				}
 
-				response.MaxPlayers = &maxPlayers
+				result.MaxPlayers = &maxPlayers
 
 				break
 			}
 		case 6:
 			{
-				response.ServerID = pointerOf(value)
+				result.ServerID = pointerOf(value)
 
 				break
 			}
@@ -257,7 +416,7 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 			}
 		case 8:
 			{
-				response.Gamemode = pointerOf(value)
+				result.Gamemode = pointerOf(value)
 
 				break
 			}
@@ -269,7 +428,7 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 					return nil, err
 				}
 
-				response.GamemodeID = &gamemodeID
+				result.GamemodeID = &gamemodeID
 
 				break
 			}
@@ -282,7 +441,7 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 				}
 
 				portIPv4Value := uint16(portIPv4)
-				response.PortIPv4 = &portIPv4Value
+				result.PortIPv4 = &portIPv4Value
 
 				break
 			}
@@ -294,13 +453,15 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 					return nil, err
 				}
 
-				response.PortIPv6 = pointerOf(uint16(portIPv6))
+				result.PortIPv6 = pointerOf(uint16(portIPv6))
 
 				break
 			}
 		}
 	}
 
+	result.Software = detectBedrockSoftware(pong.ServerGUID, splitID)
+
 	if len(motd) > 0 {
 		parsedMOTD, err := formatting.Parse(motd)
 
@@ -308,10 +469,10 @@ func getStatusBedrock(hostname string, port uint16, options ...options.StatusBed
 			return nil, err
 		}
 
-		response.MOTD = parsedMOTD
+		result.MOTD = parsedMOTD
 	}
 
-	return &response, nil
+	return &result, nil
 }
 
 func parseBedrockStatusOptions(opts ...options.StatusBedrock) options.StatusBedrock {
@@ -0,0 +1,100 @@
+package status
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mcstatus-io/mcutil/v4/response"
+)
+
+// detectBedrockSoftware guesses the server software from the fields of the unconnected pong server ID.
+// Every matching pattern adds to the score of the software, and the software with the highest score
+// is returned with its score as the confidence.
+func detectBedrockSoftware(serverGUID int64, fields []string) response.BedrockSoftware {
+	var (
+		scores  map[response.BedrockSoftwareName]float64 = make(map[response.BedrockSoftwareName]float64)
+		motd    string                                   = strings.ToLower(bedrockField(fields, 1))
+		subMOTD string                                   = strings.ToLower(bedrockField(fields, 7))
+	)
+
+	// PocketMine-MP reports its own name in place of the level name, and does not send the gamemode ID
+	// or port fields.
+	if subMOTD == "pocketmine-mp" {
+		scores[response.BedrockSoftwarePocketMine] += 0.9
+	} else if strings.Contains(motd, "pocketmine-mp") {
+		scores[response.BedrockSoftwarePocketMine] += 0.4
+	}
+
+	// Nukkit and its forks use their website as the default sub-MOTD.
+	if strings.Contains(subMOTD, "nukkit") {
+		scores[response.BedrockSoftwareNukkit] += 0.8
+	} else if strings.Contains(motd, "nukkit") {
+		scores[response.BedrockSoftwareNukkit] += 0.4
+	}
+
+	// Geyser uses "Geyser" and "Another Geyser server." as the default MOTD lines.
+	if subMOTD == "another geyser server." {
+		scores[response.BedrockSoftwareGeyser] += 0.9
+	} else if strings.Contains(motd, "geyser") || strings.Contains(subMOTD, "geyser") {
+		scores[response.BedrockSoftwareGeyser] += 0.5
+	}
+
+	if strings.Contains(motd, "endstone") || strings.Contains(subMOTD, "endstone") {
+		scores[response.BedrockSoftwareEndstone] += 0.6
+	}
+
+	// "Bedrock level" is the default level name of BDS, which is used as the sub-MOTD.
+	if subMOTD == "bedrock level" {
+		scores[response.BedrockSoftwareDedicated] += 0.4
+		scores[response.BedrockSoftwareEndstone] += 0.2
+	}
+
+	if len(fields) >= 12 {
+		// BDS and Geyser send every field, including the gamemode ID and both ports.
+		scores[response.BedrockSoftwareDedicated] += 0.3
+		scores[response.BedrockSoftwareEndstone] += 0.2
+		scores[response.BedrockSoftwareGeyser] += 0.1
+
+		// BDS listens on separate IPv4 and IPv6 ports, while Geyser reports the same port for both.
+		if bedrockField(fields, 10) == bedrockField(fields, 11) {
+			scores[response.BedrockSoftwareGeyser] += 0.2
+		}
+	} else {
+		scores[response.BedrockSoftwarePocketMine] += 0.2
+		scores[response.BedrockSoftwareNukkit] += 0.2
+	}
+
+	// The server ID is normally the GUID of the RakNet server answering the ping. A different value
+	// means the status was forwarded from another server, which is done by proxies and bridges.
+	if serverID, err := strconv.ParseInt(bedrockField(fields, 6), 10, 64); err == nil {
+		if serverID == serverGUID {
+			scores[response.BedrockSoftwareDedicated] += 0.1
+		} else {
+			scores[response.BedrockSoftwareGeyser] += 0.2
+		}
+	}
+
+	result := response.BedrockSoftware{
+		Name:       response.BedrockSoftwareUnknown,
+		Confidence: 0,
+	}
+
+	for name, score := range scores {
+		if score > result.Confidence || (score == result.Confidence && name < result.Name) {
+			result.Name = name
+			result.Confidence = score
+		}
+	}
+
+	result.Confidence = min(result.Confidence, 1)
+
+	return result
+}
+
+func bedrockField(fields []string, index int) string {
+	if index >= len(fields) {
+		return ""
+	}
+
+	return strings.TrimSpace(fields[index])
+}
@@ -1,9 +1,15 @@
 package status_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/response"
 	"github.com/mcstatus-io/mcutil/v4/status"
 	"github.com/mcstatus-io/mcutil/v4/util"
 )
@@ -17,3 +23,204 @@ func TestBedrock(t *testing.T) {
 
 	t.Logf("%+v\n", resp)
 }
+
+func TestBedrockLatency(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	go serveBedrockPongs(conn, 2)
+
+	resp, err := status.Bedrock(context.Background(), "127.0.0.1", uint16(conn.LocalAddr().(*net.UDPAddr).Port), options.StatusBedrock{
+		Timeout:     time.Second * 2,
+		PingCount:   4,
+		PingTimeout: time.Millisecond * 250,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.PingStatistics.Sent != 4 || resp.PingStatistics.Received != 3 || resp.PingStatistics.Lost != 1 {
+		t.Fatalf("unexpected ping statistics: %+v", resp.PingStatistics)
+	}
+
+	if resp.Latency <= 0 || resp.PingStatistics.Min > resp.PingStatistics.Avg || resp.PingStatistics.Avg > resp.PingStatistics.Max {
+		t.Fatalf("invalid latency values: %+v", resp.PingStatistics)
+	}
+}
+
+func TestBedrockRetransmission(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	go serveBedrockPongs(conn, 0)
+
+	resp, err := status.Bedrock(context.Background(), "127.0.0.1", uint16(conn.LocalAddr().(*net.UDPAddr).Port), options.StatusBedrock{
+		Timeout: time.Second * 2,
+		Retry: options.Retry{
+			Attempts:       3,
+			AttemptTimeout: time.Millisecond * 100,
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.PingStatistics.Sent != 1 || resp.PingStatistics.Received != 1 || resp.PingStatistics.Lost != 0 {
+		t.Fatalf("unexpected ping statistics: %+v", resp.PingStatistics)
+	}
+}
+
+func TestBedrockSoftware(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	go serveBedrockPongs(conn, -1)
+
+	resp, err := status.Bedrock(context.Background(), "127.0.0.1", uint16(conn.LocalAddr().(*net.UDPAddr).Port))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Edition == nil || !resp.Edition.Known() {
+		t.Fatalf("unexpected edition: %v", resp.Edition)
+	}
+
+	if resp.Software.Name != response.BedrockSoftwareDedicated {
+		t.Fatalf("unexpected software (expected=%s, received=%+v)", response.BedrockSoftwareDedicated, resp.Software)
+	}
+}
+
+func TestBedrockUnconnectedSocket(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	replyConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer replyConn.Close()
+
+	go serveBedrockPongsFrom(conn, replyConn, -1)
+
+	resp, err := status.Bedrock(context.Background(), "127.0.0.1", uint16(conn.LocalAddr().(*net.UDPAddr).Port), options.StatusBedrock{
+		Timeout:           time.Second * 2,
+		UnconnectedSocket: true,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.ReplyAddress != replyConn.LocalAddr().String() {
+		t.Fatalf("unexpected reply address (expected=%s, received=%s)", replyConn.LocalAddr(), resp.ReplyAddress)
+	}
+}
+
+// serveBedrockPongs replies to unconnected pings, dropping the ping with the specified index.
+func TestBedrockMalformedPong(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	go (func() {
+		data := make([]byte, 1500)
+
+		for {
+			n, addr, err := conn.ReadFrom(data)
+
+			if err != nil {
+				return
+			}
+
+			if n < 25 || data[0] != 0x01 {
+				continue
+			}
+
+			pong := bedrockPong(data[:n])
+
+			// The first reply is cut off within the server ID, and is followed by the complete reply.
+			conn.WriteTo(pong[:len(pong)-10], addr)
+			conn.WriteTo(pong, addr)
+		}
+	})()
+
+	resp, err := status.Bedrock(context.Background(), "127.0.0.1", uint16(conn.LocalAddr().(*net.UDPAddr).Port), options.StatusBedrock{
+		Timeout:   time.Second * 2,
+		PingCount: 2,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.PingStatistics.Sent != 2 || resp.PingStatistics.Received != 2 {
+		t.Fatalf("unexpected ping statistics: %+v", resp.PingStatistics)
+	}
+}
+
+func serveBedrockPongs(conn net.PacketConn, drop int) {
+	serveBedrockPongsFrom(conn, conn, drop)
+}
+
+// serveBedrockPongsFrom replies to unconnected pings received on conn using replyConn.
+func serveBedrockPongsFrom(conn, replyConn net.PacketConn, drop int) {
+	data := make([]byte, 1500)
+
+	for i := 0; ; i++ {
+		n, addr, err := conn.ReadFrom(data)
+
+		if err != nil {
+			return
+		}
+
+		if n < 25 || data[0] != 0x01 || i == drop {
+			continue
+		}
+
+		replyConn.WriteTo(bedrockPong(data[:n]), addr)
+	}
+}
+
+// bedrockPong returns the unconnected pong replying to the unconnected ping.
+func bedrockPong(ping []byte) []byte {
+	serverID := []byte("MCPE;Test Server;662;1.20.73;1;20;1234;Bedrock level;Survival;1;19132;19133;")
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte(0x1C)
+	buf.Write(ping[1:9])
+	binary.Write(buf, binary.BigEndian, int64(1234))
+	buf.Write(ping[9:25])
+	binary.Write(buf, binary.BigEndian, uint16(len(serverID)))
+	buf.Write(serverID)
+
+	return buf.Bytes()
+}
@@ -3,7 +3,6 @@ package status
 import (
 	"context"
 	"encoding/binary"
-	"fmt"
 	"net"
 	"strconv"
 	"strings"
@@ -11,6 +10,7 @@ import (
 	"unicode/utf16"
 
 	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/response"
 	"github.com/mcstatus-io/mcutil/v4/util"
@@ -29,10 +29,10 @@ func Legacy(ctx context.Context, hostname string, port uint16, options ...option
 	e := make(chan error, 1)
 
 	go func() {
-		result, err := getStatusLegacy(hostname, port, options...)
+		result, err := getStatusLegacy(ctx, hostname, port, options...)
 
 		if err != nil {
-			e <- err
+			e <- mcerrors.Classify(err)
 		} else if result != nil {
 			r <- result
 		}
@@ -41,10 +41,10 @@ func Legacy(ctx context.Context, hostname string, port uint16, options ...option
 	select {
 	case <-ctx.Done():
 		if v := ctx.Err(); v != nil {
-			return nil, v
+			return nil, mcerrors.Classify(v)
 		}
 
-		return nil, context.DeadlineExceeded
+		return nil, mcerrors.Classify(context.DeadlineExceeded)
 	case v := <-r:
 		return v, nil
 	case v := <-e:
@@ -52,7 +52,7 @@ func Legacy(ctx context.Context, hostname string, port uint16, options ...option
 	}
 }
 
-func getStatusLegacy(hostname string, port uint16, options ...options.StatusLegacy) (*response.StatusLegacy, error) {
+func getStatusLegacy(ctx context.Context, hostname string, port uint16, options ...options.StatusLegacy) (*response.StatusLegacy, error) {
 	var (
 		opts                                   = parseJavaStatusLegacyOptions(options...)
 		connectionHostname                     = hostname
@@ -74,7 +74,7 @@ func getStatusLegacy(hostname string, port uint16, options ...options.StatusLega
 		}
 	}
 
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", connectionHostname, connectionPort), opts.Timeout)
+	conn, err := dialContext(ctx, connectionHostname, connectionPort, opts.Timeout)
 
 	if err != nil {
 		return nil, err
@@ -106,7 +106,7 @@ func getStatusLegacy(hostname string, port uint16, options ...options.StatusLega
 			}
 
 			if packetType != 0xFF {
-				return nil, fmt.Errorf("status: received unexpected packet type (expected=0xFF, received=0x%02X)", packetType)
+				return nil, mcerrors.NewUnexpectedPacket("status", 0xFF, int64(packetType))
 			}
 		}
 
@@ -119,7 +119,7 @@ func getStatusLegacy(hostname string, port uint16, options ...options.StatusLega
 			}
 
 			if packetLength < 2 {
-				return nil, fmt.Errorf("status: received status response with no data (bytes=%d)", packetLength)
+				return nil, mcerrors.NewProtocolError(2, packetLength, "status: received status response with no data (bytes=%d)", packetLength)
 			}
 		}
 
@@ -144,7 +144,7 @@ func getStatusLegacy(hostname string, port uint16, options ...options.StatusLega
 			split := strings.Split(result, "\x00")
 
 			if len(split) < 6 {
-				return nil, fmt.Errorf("status: not enough information received (expected=6, received=%d)", len(split))
+				return nil, mcerrors.NewProtocolError(6, len(split), "status: not enough information received (expected=6, received=%d)", len(split))
 			}
 
 			protocolVersion, err := strconv.ParseInt(split[1], 10, 32)
@@ -196,7 +196,7 @@ func getStatusLegacy(hostname string, port uint16, options ...options.StatusLega
 		split := strings.Split(result, "\u00A7")
 
 		if len(split) < 3 {
-			return nil, fmt.Errorf("status: not enough information received (expected=3, received=%d)", len(split))
+			return nil, mcerrors.NewProtocolError(3, len(split), "status: not enough information received (expected=3, received=%d)", len(split))
 		}
 
 		motd, err := formatting.Parse(split[0])
@@ -0,0 +1,262 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/response"
+	"github.com/mcstatus-io/mcutil/v4/util"
+)
+
+var defaultManyOptions = options.Many{
+	Concurrency:  16,
+	HostInterval: 0,
+}
+
+// TargetType is the type of status retrieved from a target.
+type TargetType string
+
+var (
+	// TargetJava retrieves the status using Modern().
+	TargetJava TargetType = "java"
+	// TargetLegacy retrieves the status using Legacy().
+	TargetLegacy TargetType = "legacy"
+	// TargetBedrock retrieves the status using Bedrock().
+	TargetBedrock TargetType = "bedrock"
+)
+
+// Target is a single server to retrieve the status of using Many().
+type Target struct {
+	Type     TargetType
+	Hostname string
+	Port     uint16
+}
+
+// Result is the status of a single target returned from Many(). Only the response
+// matching the type of the target is set.
+type Result struct {
+	Target  Target
+	Modern  *response.StatusModern
+	Legacy  *response.StatusLegacy
+	Bedrock *response.StatusBedrock
+}
+
+type manyResult struct {
+	result *Result
+	err    error
+}
+
+// ParseTarget parses a host:port address into a target. The default port of the target type is used
+// if the address does not contain a port.
+func ParseTarget(targetType TargetType, address string) (Target, error) {
+	hostname, port, err := util.ParseAddress(address)
+
+	if err != nil {
+		return Target{}, err
+	}
+
+	target := Target{
+		Type:     targetType,
+		Hostname: hostname,
+	}
+
+	switch targetType {
+	case TargetJava, TargetLegacy:
+		target.Port = util.DefaultJavaPort
+	case TargetBedrock:
+		target.Port = util.DefaultBedrockPort
+	default:
+		return Target{}, fmt.Errorf("status: unknown target type: %s", targetType)
+	}
+
+	if port != nil {
+		target.Port = *port
+	}
+
+	return target, nil
+}
+
+// Many retrieves the status of all targets, running up to the configured concurrency at the same time. Results
+// are yielded in the order they complete, and a failure of one target is yielded as the error of that target
+// without stopping the others. Exactly one result is yielded for every target, so once the context is done the
+// remaining targets are yielded with the context error. Breaking out of the loop cancels all remaining targets.
+func Many(ctx context.Context, targets []Target, options ...options.Many) iter.Seq2[*Result, error] {
+	opts := parseManyOptions(options...)
+
+	return func(yield func(*Result, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+
+		defer cancel()
+
+		var (
+			jobs    chan Target     = make(chan Target)
+			results chan manyResult = make(chan manyResult)
+			limiter *hostLimiter    = newHostLimiter(opts.HostInterval)
+			wg      sync.WaitGroup
+		)
+
+		for i := 0; i < min(opts.Concurrency, len(targets)); i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for target := range jobs {
+					result, err := getTargetStatus(ctx, limiter, target, opts)
+
+					results <- manyResult{result, err}
+				}
+			}()
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+
+			for _, target := range targets {
+				select {
+				case jobs <- target:
+				case <-ctx.Done():
+					results <- manyResult{newResult(target), mcerrors.Classify(ctx.Err())}
+				}
+			}
+		}()
+
+		go func() {
+			wg.Wait()
+
+			close(results)
+		}()
+
+		for v := range results {
+			if !yield(v.result, v.err) {
+				cancel()
+
+				// Wait for all workers to exit before returning so no goroutines are left running.
+				for range results {
+				}
+
+				return
+			}
+		}
+	}
+}
+
+func newResult(target Target) *Result {
+	return &Result{
+		Target:  target,
+		Modern:  nil,
+		Legacy:  nil,
+		Bedrock: nil,
+	}
+}
+
+func getTargetStatus(ctx context.Context, limiter *hostLimiter, target Target, opts options.Many) (*Result, error) {
+	result := newResult(target)
+
+	if err := ctx.Err(); err != nil {
+		return result, mcerrors.Classify(err)
+	}
+
+	if err := limiter.wait(ctx, target.Hostname); err != nil {
+		return result, mcerrors.Classify(err)
+	}
+
+	var err error
+
+	switch target.Type {
+	case TargetJava:
+		if opts.Modern != nil {
+			result.Modern, err = Modern(ctx, target.Hostname, target.Port, *opts.Modern)
+		} else {
+			result.Modern, err = Modern(ctx, target.Hostname, target.Port)
+		}
+	case TargetLegacy:
+		if opts.Legacy != nil {
+			result.Legacy, err = Legacy(ctx, target.Hostname, target.Port, *opts.Legacy)
+		} else {
+			result.Legacy, err = Legacy(ctx, target.Hostname, target.Port)
+		}
+	case TargetBedrock:
+		if opts.Bedrock != nil {
+			result.Bedrock, err = Bedrock(ctx, target.Hostname, target.Port, *opts.Bedrock)
+		} else {
+			result.Bedrock, err = Bedrock(ctx, target.Hostname, target.Port)
+		}
+	default:
+		err = fmt.Errorf("status: unknown target type: %s", target.Type)
+	}
+
+	return result, err
+}
+
+func parseManyOptions(opts ...options.Many) options.Many {
+	if len(opts) < 1 {
+		return defaultManyOptions
+	}
+
+	result := opts[0]
+
+	if result.Concurrency < 1 {
+		result.Concurrency = defaultManyOptions.Concurrency
+	}
+
+	return result
+}
+
+// hostLimiter spaces out the requests made to the same host.
+type hostLimiter struct {
+	interval time.Duration
+	next     map[string]time.Time
+	mutex    sync.Mutex
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{
+		interval: interval,
+		next:     make(map[string]time.Time),
+	}
+}
+
+// wait blocks until a request can be made to the host, reserving the next available slot.
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mutex.Lock()
+
+	start := l.next[host]
+
+	if now := time.Now(); start.Before(now) {
+		start = now
+	}
+
+	l.next[host] = start.Add(l.interval)
+
+	l.mutex.Unlock()
+
+	delay := time.Until(start)
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,194 @@
+package status_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
+	"github.com/mcstatus-io/mcutil/v4/options"
+	"github.com/mcstatus-io/mcutil/v4/status"
+)
+
+func TestMany(t *testing.T) {
+	targets := make([]status.Target, 0)
+
+	for i := 0; i < 3; i++ {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer conn.Close()
+
+		go serveBedrockPongs(conn, -1)
+
+		target, err := status.ParseTarget(status.TargetBedrock, conn.LocalAddr().String())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targets = append(targets, target)
+	}
+
+	// A listener that is closed immediately provides a port that refuses connections.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener.Close()
+
+	target, err := status.ParseTarget(status.TargetJava, listener.Addr().String())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets = append(targets, target)
+
+	var successes, failures int
+
+	for result, err := range status.Many(context.Background(), targets, options.Many{
+		Concurrency:  2,
+		HostInterval: time.Millisecond * 10,
+		Bedrock: &options.StatusBedrock{
+			Timeout: time.Second * 2,
+		},
+	}) {
+		if err != nil {
+			if result.Target.Type != status.TargetJava || !errors.Is(err, mcerrors.ErrConnectionRefused) {
+				t.Fatalf("unexpected error for %+v: %v", result.Target, err)
+			}
+
+			failures++
+
+			continue
+		}
+
+		if result.Bedrock == nil {
+			t.Fatalf("missing Bedrock response for %+v", result.Target)
+		}
+
+		successes++
+	}
+
+	if successes != 3 || failures != 1 {
+		t.Fatalf("unexpected results (successes=%d, failures=%d)", successes, failures)
+	}
+}
+
+func TestManyContext(t *testing.T) {
+	// A socket that never responds keeps every target waiting until the context is done.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	target, err := status.ParseTarget(status.TargetBedrock, conn.LocalAddr().String())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := make([]status.Target, 20)
+
+	for i := range targets {
+		targets[i] = target
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*300)
+
+	defer cancel()
+
+	start := time.Now()
+	count := 0
+
+	for _, err := range status.Many(ctx, targets, options.Many{
+		Concurrency: 2,
+		Bedrock: &options.StatusBedrock{
+			Timeout: time.Second * 5,
+		},
+	}) {
+		if !errors.Is(err, mcerrors.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the context error, received %v", err)
+		}
+
+		count++
+	}
+
+	if count != len(targets) {
+		t.Fatalf("expected %d results, received %d", len(targets), count)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second*2 {
+		t.Fatalf("expected the remaining targets to be cancelled, took %s", elapsed)
+	}
+}
+
+func TestManyContextClosesConnections(t *testing.T) {
+	// A listener that never responds keeps the status request waiting until the context is done.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	closed := make(chan struct{})
+
+	go (func() {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		conn.Read(make([]byte, 1024))
+
+		for {
+			if _, err := conn.Read(make([]byte, 1024)); err != nil {
+				close(closed)
+
+				return
+			}
+		}
+	})()
+
+	target, err := status.ParseTarget(status.TargetJava, listener.Addr().String())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+
+	defer cancel()
+
+	for _, err := range status.Many(ctx, []status.Target{target}, options.Many{
+		Modern: &options.StatusModern{
+			Timeout: time.Second * 10,
+		},
+	}) {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the context error, received %v", err)
+		}
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second * 2):
+		t.Fatal("expected the connection to be closed once the context was done")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
-	"fmt"
 	"io"
 	"log"
 	"math/rand"
@@ -13,6 +12,7 @@ import (
 	"time"
 
 	"github.com/mcstatus-io/mcutil/v4/formatting"
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/proto"
 	"github.com/mcstatus-io/mcutil/v4/response"
@@ -69,10 +69,10 @@ func Modern(ctx context.Context, hostname string, port uint16, options ...option
 	e := make(chan error, 1)
 
 	go func() {
-		result, err := getStatusModern(hostname, port, options...)
+		result, err := getStatusModern(ctx, hostname, port, options...)
 
 		if err != nil {
-			e <- err
+			e <- mcerrors.Classify(err)
 		} else if result != nil {
 			r <- result
 		}
@@ -81,10 +81,10 @@ func Modern(ctx context.Context, hostname string, port uint16, options ...option
 	select {
 	case <-ctx.Done():
 		if v := ctx.Err(); v != nil {
-			return nil, v
+			return nil, mcerrors.Classify(v)
 		}
 
-		return nil, context.DeadlineExceeded
+		return nil, mcerrors.Classify(context.DeadlineExceeded)
 	case v := <-r:
 		return v, nil
 	case v := <-e:
@@ -92,7 +92,7 @@ func Modern(ctx context.Context, hostname string, port uint16, options ...option
 	}
 }
 
-func getStatusModern(hostname string, port uint16, options ...options.StatusModern) (*response.StatusModern, error) {
+func getStatusModern(ctx context.Context, hostname string, port uint16, options ...options.StatusModern) (*response.StatusModern, error) {
 	var (
 		opts                                   = parseJavaStatusOptions(options...)
 		connectionHostname string              = hostname
@@ -122,7 +122,7 @@ func getStatusModern(hostname string, port uint16, options ...options.StatusMode
 		}
 	}
 
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", connectionHostname, connectionPort), opts.Timeout)
+	conn, err := dialContext(ctx, connectionHostname, connectionPort, opts.Timeout)
 
 	if err != nil {
 		return nil, err
@@ -259,7 +259,7 @@ func readJavaStatusStatusResponsePacket(r io.Reader, result any) error {
 		}
 
 		if packetType != 0x00 {
-			return fmt.Errorf("status: received unexpected packet type (expected=0x00, received=0x%02X)", packetType)
+			return mcerrors.NewUnexpectedPacket("status", 0x00, int64(packetType))
 		}
 	}
 
@@ -314,7 +314,7 @@ func readJavaStatusPongPacket(r io.Reader, payload int64) error {
 		}
 
 		if packetType != 0x01 {
-			return fmt.Errorf("status: received unexpected packet type (expected=0x01, received=0x%02X)", packetType)
+			return mcerrors.NewUnexpectedPacket("status", 0x01, int64(packetType))
 		}
 	}
 
@@ -327,7 +327,7 @@ func readJavaStatusPongPacket(r io.Reader, payload int64) error {
 		}
 
 		if payload != returnPayload {
-			return fmt.Errorf("status: received unexpected payload (expected=%X, received=%x)", payload, returnPayload)
+			return mcerrors.NewPayloadMismatch("status", "payload", payload, returnPayload)
 		}
 	}
 
@@ -354,7 +354,7 @@ func formatJavaStatusResponse(serverResponse rawJavaStatus, srvRecord *response.
 			uuid, ok := parsePlayerID(player.ID)
 
 			if !ok {
-				return nil, fmt.Errorf("status: invalid player UUID: %+v", player.ID)
+				return nil, mcerrors.NewProtocolError(nil, player.ID, "status: invalid player UUID: %+v", player.ID)
 			}
 
 			samplePlayers = append(samplePlayers, response.SamplePlayer{
@@ -2,11 +2,11 @@ package status
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"net"
 	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
 	"github.com/mcstatus-io/mcutil/v4/util"
 )
@@ -17,10 +17,10 @@ func ModernRaw(ctx context.Context, hostname string, port uint16, options ...opt
 	e := make(chan error, 1)
 
 	go func() {
-		result, err := getStatusRaw(hostname, port, options...)
+		result, err := getStatusRaw(ctx, hostname, port, options...)
 
 		if err != nil {
-			e <- err
+			e <- mcerrors.Classify(err)
 		} else if result != nil {
 			r <- result
 		}
@@ -29,10 +29,10 @@ func ModernRaw(ctx context.Context, hostname string, port uint16, options ...opt
 	select {
 	case <-ctx.Done():
 		if v := ctx.Err(); v != nil {
-			return nil, v
+			return nil, mcerrors.Classify(v)
 		}
 
-		return nil, context.DeadlineExceeded
+		return nil, mcerrors.Classify(context.DeadlineExceeded)
 	case v := <-r:
 		return v, nil
 	case v := <-e:
@@ -40,7 +40,7 @@ func ModernRaw(ctx context.Context, hostname string, port uint16, options ...opt
 	}
 }
 
-func getStatusRaw(hostname string, port uint16, options ...options.StatusModern) (map[string]any, error) {
+func getStatusRaw(ctx context.Context, hostname string, port uint16, options ...options.StatusModern) (map[string]any, error) {
 	var (
 		opts                              = parseJavaStatusOptions(options...)
 		connectionHostname                = hostname
@@ -58,7 +58,7 @@ func getStatusRaw(hostname string, port uint16, options ...options.StatusModern)
 		}
 	}
 
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", connectionHostname, connectionPort), opts.Timeout)
+	conn, err := dialContext(ctx, connectionHostname, connectionPort, opts.Timeout)
 
 	if err != nil {
 		return nil, err
@@ -2,8 +2,11 @@ package status
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"net"
+	"time"
 
 	"github.com/mcstatus-io/mcutil/v4/proto"
 )
@@ -54,3 +57,43 @@ func parsePlayerID(value any) (string, bool) {
 func pointerOf[T any](v T) *T {
 	return &v
 }
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+// dialContext connects to the address using TCP, giving up after the timeout or once the context is done. The
+// connection is also closed once the context is done, so that any blocking reads and writes return.
+func dialContext(ctx context.Context, hostname string, port uint16, timeout time.Duration) (net.Conn, error) {
+	dialer := net.Dialer{
+		Timeout: timeout,
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", hostname, port))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &contextConn{
+		Conn: conn,
+		stop: context.AfterFunc(ctx, func() { conn.Close() }),
+	}, nil
+}
+
+// contextConn is a connection that is closed once its context is done.
+type contextConn struct {
+	net.Conn
+	stop func() bool
+}
+
+// Close stops watching the context and closes the connection.
+func (c *contextConn) Close() error {
+	c.stop()
+
+	return c.Conn.Close()
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mcstatus-io/mcutil/v4/mcerrors"
 	"github.com/mcstatus-io/mcutil/v4/options"
 )
 
@@ -56,16 +57,16 @@ func SendVote(ctx context.Context, host string, port uint16, opts options.Vote)
 	e := make(chan error, 1)
 
 	go func() {
-		e <- sendVote(host, port, opts)
+		e <- mcerrors.Classify(sendVote(host, port, opts))
 	}()
 
 	select {
 	case <-ctx.Done():
 		if v := ctx.Err(); v != nil {
-			return v
+			return mcerrors.Classify(v)
 		}
 
-		return context.DeadlineExceeded
+		return mcerrors.Classify(context.DeadlineExceeded)
 	case v := <-e:
 		return v
 	}
@@ -104,7 +105,7 @@ func sendVote(host string, port uint16, opts options.Vote) error {
 		dataSegments := strings.Split(string(data[:len(data)-1]), " ")
 
 		if len(dataSegments) < 2 {
-			return fmt.Errorf("vote: server sent invalid handshake packet with value: %s", data[:len(data)-1])
+			return mcerrors.NewProtocolError(nil, string(data[:len(data)-1]), "vote: server sent invalid handshake packet with value: %s", data[:len(data)-1])
 		}
 
 		version = dataSegments[1]
@@ -116,7 +117,7 @@ func sendVote(host string, port uint16, opts options.Vote) error {
 	}
 
 	if majorVersion != "2" && majorVersion != "1" {
-		return fmt.Errorf("vote: unknown Votifier version: %s", version)
+		return mcerrors.NewProtocolError(nil, version, "vote: unknown Votifier version: %s", version)
 	}
 
 	if majorVersion == "2" && len(opts.Token) > 0 {
@@ -253,9 +254,14 @@ func sendVotifier2Vote(r *bufio.Reader, conn net.Conn, host string, port uint16,
 		case "ok":
 			break
 		case "error":
+			// NuVotifier rejects votes signed with the wrong token with a signature error.
+			if strings.Contains(strings.ToLower(response.Error), "signature") {
+				return mcerrors.New(mcerrors.ErrAuth, fmt.Sprintf("vote: server returned error: %s", response.Error))
+			}
+
 			return fmt.Errorf("vote: server returned error: %s", response.Error)
 		default:
-			return fmt.Errorf("vote: received unexpected server response (expected=<nil>, received=%s)", response.Status)
+			return mcerrors.NewProtocolError("ok", response.Status, "vote: received unexpected server response (expected=ok, received=%s)", response.Status)
 		}
 	}
 